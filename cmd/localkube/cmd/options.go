@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"net"
 
 	flag "github.com/spf13/pflag"
@@ -37,6 +38,7 @@ func NewLocalkubeServer() *localkube.LocalkubeServer {
 		APIServerInsecureAddress: net.ParseIP("127.0.0.1"),
 		APIServerInsecurePort:    0,
 		APIServerName:            constants.APIServerName,
+		HealthPort:               util.LocalkubeHealthPort,
 		ShouldGenerateKubeconfig: false,
 		ShouldGenerateCerts:      true,
 		ShowVersion:              false,
@@ -59,6 +61,8 @@ func AddFlags(s *localkube.LocalkubeServer) {
 	flag.IntVar(&s.APIServerInsecurePort, "apiserver-insecure-port", s.APIServerInsecurePort, "The port the apiserver will listen insecurely on")
 	flag.StringVar(&s.APIServerName, "apiserver-name", s.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the API server available from outside the machine")
 
+	flag.IntVar(&s.HealthPort, "health-port", s.HealthPort, "The port localkube reports the status of its components on, on localhost. Clients need a cert signed by the cluster CA. 0 disables it")
+
 	flag.BoolVar(&s.ShouldGenerateKubeconfig, "generate-kubeconfig", s.ShouldGenerateKubeconfig, "If localkube should generate its own kubeconfig")
 	flag.BoolVar(&s.ShouldGenerateCerts, "generate-certs", s.ShouldGenerateCerts, "If localkube should generate it's own certificates")
 	flag.BoolVar(&s.ShowVersion, "show-version", s.ShowVersion, "If localkube should just print the version and exit.")
@@ -70,6 +74,11 @@ func AddFlags(s *localkube.LocalkubeServer) {
 	flag.StringVar(&s.RemoteImageEndpoint, "remote-image-endpoint", "", "The container image endpoint (CRI) to be used (if this is set, then --container-runtime is forced as 'remote')")
 	flag.StringVar(&s.NetworkPlugin, "network-plugin", "", "The name of the network plugin")
 	flag.StringVar(&s.FeatureGates, "feature-gates", "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	flag.StringSliceVar(&s.DisableComponents, "disable-components", s.DisableComponents, fmt.Sprintf("Components localkube shouldn't run, out of %v", localkube.Components))
+	flag.StringSliceVar(&s.EtcdServers, "etcd-servers", s.EtcdServers, "The etcd servers the apiserver uses instead of the embedded etcd, which has to be disabled with --disable-components=etcd")
+	flag.StringVar(&s.EtcdCAFile, "etcd-cafile", s.EtcdCAFile, "The CA file used to verify https --etcd-servers")
+	flag.StringVar(&s.EtcdCertFile, "etcd-certfile", s.EtcdCertFile, "The client certificate used for https --etcd-servers")
+	flag.StringVar(&s.EtcdKeyFile, "etcd-keyfile", s.EtcdKeyFile, "The client key used for https --etcd-servers")
 	flag.Var(&s.ExtraConfig, "extra-config", "A set of key=value pairs that describe configuration that may be passed to different components. The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.")
 
 	// These two come from vendor/ packages that use flags. We should hide them
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/golang/glog"
 	"k8s.io/apiserver/pkg/util/feature"
@@ -46,21 +47,34 @@ func StartLocalkube() {
 		os.Exit(0)
 	}
 
+	if err := Server.ValidateComponents(); err != nil {
+		fmt.Println("Invalid components:", err)
+		os.Exit(1)
+	}
+
 	if os.Geteuid() != 0 {
 		fmt.Println("localkube should run as root!")
 		os.Exit(1)
 	}
 
-	SetupServer(Server)
-	Server.StartAll()
-
-	defer Server.StopAll()
-
+	// systemd stops localkube with SIGTERM, which may come while it starts
 	interruptChan := make(chan os.Signal, 1)
-	signal.Notify(interruptChan, os.Interrupt)
+	signal.Notify(interruptChan, os.Interrupt, syscall.SIGTERM)
 
-	<-interruptChan
-	fmt.Println("Shutting down...")
+	SetupServer(Server)
+	go Server.StartAll()
+
+	sig := <-interruptChan
+	fmt.Printf("Received %s, shutting down...\n", sig)
+	if err := Server.StopAll(); err != nil {
+		fmt.Println("Error shutting down:", err)
+		code := 1
+		if e, ok := err.(*localkube.ShutdownError); ok {
+			code = e.ExitCode()
+		}
+		os.Exit(code)
+	}
+	fmt.Println("Shut down cleanly")
 }
 
 func SetupServer(s *localkube.LocalkubeServer) {
@@ -110,36 +124,41 @@ func SetupServer(s *localkube.LocalkubeServer) {
 	}
 	capabilities.Initialize(c)
 
-	// setup etcd
-	etcd, err := s.NewEtcd(s.GetEtcdDataDirectory())
-	if err != nil {
-		panic(err)
+	if s.IsEnabled(localkube.EtcdName) {
+		// setup etcd, which is started first
+		etcd, err := s.NewEtcd(s.GetEtcdDataDirectory())
+		if err != nil {
+			panic(err)
+		}
+		s.AddServer(etcd)
+	} else {
+		fmt.Printf("Using etcd at %v\n", s.EtcdServers)
 	}
-	// Start etcd first
-	etcd.Start()
 
 	// setup access to etcd
 	netIP, _ := s.GetHostIP()
 	fmt.Printf("localkube host ip address: %s\n", netIP.String())
 
-	// setup apiserver
-	apiserver := s.NewAPIServer()
-	s.AddServer(apiserver)
-
-	// setup controller-manager
-	controllerManager := s.NewControllerManagerServer()
-	s.AddServer(controllerManager)
-
-	// setup scheduler
-	scheduler := s.NewSchedulerServer()
-	s.AddServer(scheduler)
-
-	// setup kubelet
-	kubelet := s.NewKubeletServer()
-	s.AddServer(kubelet)
+	// setup the servers in the order they are started, skipping the disabled ones
+	for _, server := range []struct {
+		name  string
+		setup func() localkube.Server
+	}{
+		{localkube.APIServerName, s.NewAPIServer},
+		{localkube.ControllerManagerName, s.NewControllerManagerServer},
+		{localkube.SchedulerName, s.NewSchedulerServer},
+		{localkube.KubeletName, s.NewKubeletServer},
+		{localkube.ProxyName, s.NewProxyServer},
+	} {
+		if !s.IsEnabled(server.name) {
+			fmt.Printf("Not running %s, it is disabled\n", server.name)
+			continue
+		}
+		s.AddServer(server.setup())
+	}
 
-	// setup proxy
-	proxy := s.NewProxyServer()
-	s.AddServer(proxy)
+	if err := s.StartHealthServer(s.Servers); err != nil {
+		glog.Errorf("Unable to serve the status of the components: %s", err)
+	}
 
 }
@@ -18,12 +18,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+
+	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
-	"os"
 )
 
 // cacheCmd represents the cache command
@@ -39,8 +42,12 @@ var addCacheCmd = &cobra.Command{
 	Short: "Add an image to local cache.",
 	Long:  "Add an image to local cache.",
 	Run: func(cmd *cobra.Command, args []string) {
-		// Cache and load images into docker daemon
-		if err := machine.CacheAndLoadImages(args); err != nil {
+		// Cache and load images into the container runtime
+		cc, err := loadConfigFromFile(viper.GetString(config.MachineProfile))
+		if err != nil && !os.IsNotExist(err) {
+			glog.Errorf("Error loading profile config: %s", err)
+		}
+		if err := machine.CacheAndLoadImages(args, cc.KubernetesConfig.ContainerRuntime); err != nil {
 			fmt.Fprintf(os.Stderr, "Error caching and loading images: %s\n", err)
 			os.Exit(1)
 		}
@@ -72,7 +79,7 @@ var deleteCacheCmd = &cobra.Command{
 }
 
 // LoadCachedImagesInConfigFile loads the images currently in the config file (minikube start)
-func LoadCachedImagesInConfigFile() error {
+func LoadCachedImagesInConfigFile(containerRuntime string) error {
 	configFile, err := config.ReadConfig()
 	if err != nil {
 		return err
@@ -82,7 +89,7 @@ func LoadCachedImagesInConfigFile() error {
 		for key := range values.(map[string]interface{}) {
 			images = append(images, key)
 		}
-		return machine.CacheAndLoadImages(images)
+		return machine.CacheAndLoadImages(images, containerRuntime)
 	}
 	return nil
 }
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// verifyCacheCmd represents the cache verify command
+var verifyCacheCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the checksums of all cached artifacts.",
+	Long: `Verify the SHA-256 checksums of all cached artifacts (ISOs, binaries and images).
+Artifacts that fail verification are downloaded again the next time they are used.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		failed, err := verifyCache(constants.MakeMiniPath("cache"), os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error verifying cache: %s\n", err)
+			os.Exit(1)
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%d cached artifact(s) failed verification\n", failed)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(verifyCacheCmd)
+}
+
+// verifyCache checks every artifact under dir against its checksum, writing the result for each
+// to w. It returns the number of artifacts that failed verification.
+func verifyCache(dir string, w io.Writer) (int, error) {
+	failed := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		// Skip checksums themselves and partial downloads
+		if info.IsDir() || util.IsChecksumFile(path) || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		if err := util.VerifyChecksumFile(path); err != nil {
+			failed++
+			fmt.Fprintf(w, "FAILED\t%s: %s\n", rel, err)
+			return nil
+		}
+		fmt.Fprintf(w, "OK\t%s\n", rel)
+		return nil
+	})
+	return failed, err
+}
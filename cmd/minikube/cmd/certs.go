@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+var rotateCA bool
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Inspect and renew the certificates of the local cluster",
+	Long:  "Inspect and renew the certificates minikube generates for the apiserver and its clients.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var certsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Lists the certificates with their expiry and subject alternative names",
+	Long:  "Lists the certificates with their expiry and subject alternative names.",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Without a profile config, the certs are those of a profile with its own CA
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err == nil && migrateLegacyCerts() {
+			cc.KubernetesConfig.SharedCA = true
+		}
+		certs, err := bootstrapper.CheckCerts(cc.KubernetesConfig)
+		if err != nil {
+			glog.Errorln("Error checking certs:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		printCerts(os.Stdout, certs, time.Now())
+		if len(bootstrapper.ExpiringCerts(certs, time.Now(), bootstrapper.CertExpiryWarning)) > 0 {
+			fmt.Fprintln(os.Stderr, "Some certificates expire soon, run 'minikube certs renew' to renew them.")
+			os.Exit(1)
+		}
+	},
+}
+
+var certsRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renews the leaf certificates with their existing keys",
+	Long: `Signs the apiserver, client and proxy-client certificates again with their existing keys, which
+extends their expiry and picks up changes to --apiserver-names and --apiserver-ips. The certificates are
+copied into the VM and the control plane is restarted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// SetupCerts signs the leaf certificates again with their existing keys
+		updateClusterCerts(nil)
+		fmt.Println("Certificates renewed.")
+	},
+}
+
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotates the keys of the leaf certificates, or of the whole CA",
+	Long: `Generates new keys for the apiserver, client and proxy-client certificates and signs them again.
+With --ca the certificate authorities are replaced too, and anything that trusted the old CA has to be
+updated. The certificates are copied into the VM and the control plane is restarted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateClusterCerts(func(k8s bootstrapper.KubernetesConfig) error {
+			if rotateCA && k8s.SharedCA {
+				fmt.Fprintln(os.Stderr, "Warning: the CA is shared, the other profiles that use it need 'minikube certs renew' to trust the new CA.")
+			}
+			return bootstrapper.RotateCerts(k8s, rotateCA)
+		})
+		fmt.Println("Certificates rotated.")
+	},
+}
+
+// updateClusterCerts regenerates the certs of a running cluster with fn, if
+// any, signs the leaf certs again, copies them into the VM and restarts the
+// control plane.
+func updateClusterCerts(fn func(bootstrapper.KubernetesConfig) error) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	defer api.Close()
+
+	ms, err := cluster.GetHostStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting machine status:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if ms != state.Running.String() {
+		fmt.Fprintln(os.Stderr, "minikube is not running, start it before updating its certificates.")
+		os.Exit(1)
+	}
+
+	migrateLegacyCerts()
+	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		glog.Exitf("Error loading profile config: %s", err)
+	}
+	k8s := cc.KubernetesConfig
+	ip, err := cluster.GetHostDriverIP(api)
+	if err != nil {
+		glog.Exitf("Error getting VM IP address: %s", err)
+	}
+	k8s.NodeIP = ip.String()
+
+	clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		glog.Exitf("Error getting cluster bootstrapper: %s", err)
+	}
+
+	if fn != nil {
+		if err := fn(k8s); err != nil {
+			glog.Errorln("Error generating certs:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	}
+	if err := clusterBootstrapper.SetupCerts(k8s); err != nil {
+		glog.Errorln("Error copying certs:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	fmt.Println("Restarting the control plane...")
+	if err := clusterBootstrapper.ReloadCerts(k8s); err != nil {
+		glog.Errorln("Error restarting the control plane:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+}
+
+// printCerts writes a table of the certificates and when they expire
+func printCerts(out io.Writer, certs []bootstrapper.CertInfo, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSUBJECT\tEXPIRES\tSANS")
+	for _, c := range certs {
+		expires := c.NotAfter.Format("2006-01-02")
+		if left := c.NotAfter.Sub(now); left <= 0 {
+			expires += " (expired)"
+		} else if left < bootstrapper.CertExpiryWarning {
+			expires += fmt.Sprintf(" (%d days left)", int(left.Hours()/24))
+		}
+		name := c.Name
+		if c.IsCA {
+			name += " (CA)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, c.Subject, expires, strings.Join(c.SANs(), ","))
+	}
+	w.Flush()
+}
+
+// migrateLegacyCerts copies the certs of an existing profile out of the minikube
+// home, and saves that the profile uses the shared CA. It returns whether the
+// certs were migrated.
+func migrateLegacyCerts() bool {
+	migrated, err := bootstrapper.MigrateLegacyCerts(cfg.GetMachineName())
+	if err != nil {
+		glog.Exitf("Error migrating certificates: %s", err)
+	}
+	if !migrated {
+		return false
+	}
+	fmt.Printf("Copied the certificates of profile %s into %s, it keeps using the shared CA in %s\n",
+		cfg.GetMachineName(), bootstrapper.GetCertsDir(cfg.GetMachineName()), constants.GetMinipath())
+	if cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile)); err == nil {
+		cc.KubernetesConfig.SharedCA = true
+		if err := saveConfig(cc); err != nil {
+			glog.Exitf("Error saving profile config: %s", err)
+		}
+	}
+	return true
+}
+
+// warnExpiringCerts prints a warning for the certificates that expire soon
+func warnExpiringCerts(k8s bootstrapper.KubernetesConfig) {
+	certs, err := bootstrapper.CheckCerts(k8s)
+	if err != nil {
+		glog.Warningf("Unable to check certificate expiry: %s", err)
+		return
+	}
+	for _, c := range bootstrapper.ExpiringCerts(certs, time.Now(), bootstrapper.CertExpiryWarning) {
+		fmt.Fprintf(os.Stderr, "Warning: certificate %s expires on %s, run 'minikube certs renew' or 'minikube certs rotate --ca' to replace it.\n",
+			c.Name, c.NotAfter.Format("2006-01-02"))
+	}
+}
+
+func init() {
+	certsRotateCmd.Flags().BoolVar(&rotateCA, "ca", false, "Replace the certificate authorities as well as the leaf certificates")
+	certsCmd.AddCommand(certsCheckCmd)
+	certsCmd.AddCommand(certsRenewCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+	RootCmd.AddCommand(certsCmd)
+}
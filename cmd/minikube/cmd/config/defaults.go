@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	defaultsComponent         string
+	defaultsKubernetesVersion string
+	defaultsExtraOptions      util.ExtraOptionSlice
+)
+
+var configDefaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Display the flags minikube generates for a Kubernetes component",
+	Long: `Display the flags minikube generates for a Kubernetes component with the kubeadm bootstrapper.
+Pass --extra-config to see how it changes the defaults, e.g. --extra-config=kubelet.cadvisor-port- removes a flag.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube config defaults --component=kubelet --kubernetes-version=vX.Y.Z")
+			os.Exit(1)
+		}
+		if err := configDefaults(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configDefaultsCmd.Flags().StringVar(&defaultsComponent, "component", kubeadm.Kubelet, fmt.Sprintf("The component to display the flags of. One of %v", kubeadm.Components()))
+	configDefaultsCmd.Flags().StringVar(&defaultsKubernetesVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The kubernetes version to display the flags of (ex: v1.2.3)")
+	configDefaultsCmd.Flags().Var(&defaultsExtraOptions, "extra-config", "A set of key=value pairs to apply on top of the defaults, as with minikube start")
+	ConfigCmd.AddCommand(configDefaultsCmd)
+}
+
+func configDefaults(out io.Writer) error {
+	opts, err := kubeadm.ComponentOptions(defaultsComponent, bootstrapper.KubernetesConfig{
+		KubernetesVersion: defaultsKubernetesVersion,
+		ExtraOptions:      defaultsExtraOptions,
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := []string{}
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(out, "--%s=%s\n", k, opts[k])
+	}
+	return nil
+}
@@ -17,15 +17,19 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/storageclass"
 )
@@ -123,9 +127,17 @@ func EnableOrDisableAddon(name string, val string) error {
 		return errors.Wrap(err, "getting command runner")
 	}
 	if enable {
-		for _, addon := range addon.Assets {
-			if err := cmd.Copy(addon); err != nil {
-				return errors.Wrapf(err, "error enabling addon %s: %s", addon.AssetName)
+		data, err := addonTemplateData()
+		if err != nil {
+			return errors.Wrap(err, "getting addon template data")
+		}
+		files, err := addon.Files(data)
+		if err != nil {
+			return errors.Wrapf(err, "rendering addon %s", name)
+		}
+		for _, f := range files {
+			if err := cmd.Copy(f); err != nil {
+				return errors.Wrapf(err, "error enabling addon %s: %s", name, f.GetTargetName())
 			}
 		}
 	} else {
@@ -138,6 +150,20 @@ func EnableOrDisableAddon(name string, val string) error {
 	return nil
 }
 
+// addonTemplateData returns the values the addons of the current profile are
+// rendered with.  Without a profile config, the defaults are used.
+func addonTemplateData() (assets.AddonTemplateData, error) {
+	var cc cluster.Config
+	data, err := ioutil.ReadFile(constants.GetProfileFile(config.GetMachineName()))
+	if err == nil {
+		err = json.Unmarshal(data, &cc)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		glog.Warningf("Unable to load profile config, using the default service CIDR: %s", err)
+	}
+	return assets.NewAddonTemplateData(cc.KubernetesConfig.ServiceCIDR)
+}
+
 func EnableOrDisableDefaultStorageClass(name, val string) error {
 	enable, err := strconv.ParseBool(val)
 	if err != nil {
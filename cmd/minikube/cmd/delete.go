@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	pkg_config "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -59,6 +60,10 @@ associated files.`,
 			fmt.Println("Errors occurred deleting mount process: ", err)
 		}
 
+		if err := bootstrapper.DeleteCerts(pkg_config.GetMachineName()); err != nil {
+			fmt.Println("Error deleting machine certificates: ", err)
+		}
+
 		if err := os.Remove(constants.GetProfileFile(viper.GetString(pkg_config.MachineProfile))); err != nil {
 			fmt.Println("Error deleting machine profile config")
 			os.Exit(1)
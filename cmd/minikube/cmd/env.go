@@ -34,6 +34,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
@@ -315,6 +316,17 @@ var dockerEnvCmd = &cobra.Command{
 			fmt.Println(`'none' driver does not support 'minikube docker-env' command`)
 			os.Exit(0)
 		}
+		if !unset {
+			cr, err := getContainerRuntime(host)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting container runtime: %s\n", err)
+				os.Exit(1)
+			}
+			if _, ok := cr.(*cruntime.Docker); !ok {
+				fmt.Fprintf(os.Stderr, "'minikube docker-env' is only supported with the docker container runtime, the cluster uses %s\n", cr.Name())
+				os.Exit(1)
+			}
+		}
 
 		var shellCfg *ShellConfig
 
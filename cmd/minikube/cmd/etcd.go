@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// etcdCmd represents the etcd command
+var etcdCmd = &cobra.Command{
+	Use:   "etcd",
+	Short: "Maintenance of the etcd of the local cluster",
+	Long:  "Maintenance of the etcd the local cluster stores its state in.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var etcdDefragCmd = &cobra.Command{
+	Use:   "defrag",
+	Short: "Defragments the etcd database to release the space of deleted keys",
+	Long: `Defragments the etcd database, which releases the space of deleted and compacted keys to the
+filesystem. etcd doesn't serve requests while it is defragmented.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		maintainEtcd(bootstrapper.EtcdDefrag)
+	},
+}
+
+var etcdCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Compacts the history of the etcd database up to the current revision",
+	Long: `Drops the history of the keys in the etcd database before the current revision. Run
+'minikube etcd defrag' afterwards to release the space to the filesystem.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		maintainEtcd(bootstrapper.EtcdCompact)
+	},
+}
+
+// maintainEtcd runs a maintenance operation on the etcd of a running cluster
+func maintainEtcd(op string) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	defer api.Close()
+
+	ms, err := cluster.GetHostStatus(api)
+	if err != nil {
+		glog.Errorln("Error getting machine status:", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	if ms != state.Running.String() {
+		fmt.Fprintln(os.Stderr, "minikube is not running, start it before maintaining etcd.")
+		os.Exit(1)
+	}
+
+	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		glog.Exitf("Error loading profile config: %s", err)
+	}
+	clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		glog.Exitf("Error getting cluster bootstrapper: %s", err)
+	}
+
+	out, err := clusterBootstrapper.MaintainEtcd(cc.KubernetesConfig, op)
+	if err != nil {
+		glog.Errorf("Error running etcd %s: %s", op, err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	fmt.Println(out)
+}
+
+func init() {
+	etcdCmd.AddCommand(etcdDefragCmd)
+	etcdCmd.AddCommand(etcdCompactCmd)
+	RootCmd.AddCommand(etcdCmd)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util/kubeconfig"
+)
+
+var (
+	userGroups      []string
+	userExpiry      time.Duration
+	userOutput      string
+	userClusterRole string
+	userRole        string
+	userNamespace   string
+)
+
+// kubeconfigCmd represents the kubeconfig command
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Manage the kubeconfig entries of the local cluster",
+	Long:  "Manage the kubeconfig entries of the local cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var kubeconfigAddUserCmd = &cobra.Command{
+	Use:   "add-user NAME",
+	Short: "Issues a client certificate for another user of the cluster",
+	Long: `Issues a client certificate for the user NAME, signed by the cluster CA, and adds a NAME@<profile> context
+for it to the kubeconfig, or writes a standalone kubeconfig with --output. The certificate organizations are the
+groups of the user. --cluster-role and --role bind the user to an existing ClusterRole or Role.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube kubeconfig add-user NAME [--group=GROUP]...")
+			os.Exit(1)
+		}
+		name := args[0]
+		if userExpiry <= 0 {
+			fmt.Fprintf(os.Stderr, "--expiry must be positive, got %s\n", userExpiry)
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		ms, err := cluster.GetHostStatus(api)
+		if err != nil {
+			glog.Errorln("Error getting machine status:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if ms != state.Running.String() {
+			fmt.Fprintln(os.Stderr, "minikube is not running, start it before adding users.")
+			os.Exit(1)
+		}
+		ip, err := cluster.GetHostDriverIP(api)
+		if err != nil {
+			glog.Exitf("Error getting VM IP address: %s", err)
+		}
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err != nil {
+			glog.Exitf("Error loading profile config: %s", err)
+		}
+
+		certPath, keyPath, err := bootstrapper.GenerateUserCert(cc.KubernetesConfig, name, userGroups, userExpiry)
+		if err != nil {
+			glog.Errorln("Error generating user certificate:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		clusterName := cfg.GetMachineName()
+		contextName := fmt.Sprintf("%s@%s", name, clusterName)
+		kubeCfgSetup := &kubeconfig.KubeConfigSetup{
+			ClusterName:          clusterName,
+			ClusterServerAddress: fmt.Sprintf("https://%s:%d", ip, bootstrapper.GetAPIServerPort(cc.KubernetesConfig)),
+			ClientCertificate:    certPath,
+			ClientKey:            keyPath,
+			CertificateAuthority: bootstrapper.CertPath(cc.KubernetesConfig, "ca.crt"),
+			UserName:             name,
+			ContextName:          contextName,
+			// A standalone kubeconfig is only for this user
+			KeepContext: userOutput == "",
+		}
+		kubeConfigFile := userOutput
+		if kubeConfigFile == "" {
+			kubeConfigFile = cmdUtil.GetKubeConfigPath()
+		}
+		kubeCfgSetup.SetKubeConfigFile(kubeConfigFile)
+		if err := kubeconfig.SetupKubeConfig(kubeCfgSetup); err != nil {
+			glog.Errorln("Error setting up kubeconfig:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		if err := bindUser(name); err != nil {
+			glog.Errorln("Error binding user:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		if userOutput != "" {
+			fmt.Printf("Wrote a kubeconfig for %s to %s\n", name, userOutput)
+		} else {
+			fmt.Printf("Added user %s to %s, use it with: kubectl --context=%s\n", name, kubeConfigFile, contextName)
+		}
+	},
+}
+
+// bindUser creates the bindings requested with --cluster-role and --role, using
+// the credentials of the minikube context.
+func bindUser(name string) error {
+	if userClusterRole == "" && userRole == "" {
+		return nil
+	}
+	// The new user has no permissions yet, so bind it as the cluster admin
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: cmdUtil.GetKubeConfigPath()}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.GetMachineName()}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return errors.Wrap(err, "loading kubeconfig")
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+
+	subjects := []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: name}}
+	if userClusterRole != "" {
+		binding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", name, userClusterRole)},
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: userClusterRole},
+		}
+		if _, err := client.RbacV1().ClusterRoleBindings().Create(binding); err != nil {
+			return errors.Wrapf(err, "binding %s to cluster role %s", name, userClusterRole)
+		}
+	}
+	if userRole != "" {
+		binding := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", name, userRole), Namespace: userNamespace},
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: userRole},
+		}
+		if _, err := client.RbacV1().RoleBindings(userNamespace).Create(binding); err != nil {
+			return errors.Wrapf(err, "binding %s to role %s in %s", name, userRole, userNamespace)
+		}
+	}
+	return nil
+}
+
+func init() {
+	kubeconfigAddUserCmd.Flags().StringArrayVar(&userGroups, "group", nil, "A group of the user, written as an organization of the certificate. Can be repeated")
+	kubeconfigAddUserCmd.Flags().DurationVar(&userExpiry, "expiry", 365*24*time.Hour, "How long the certificate of the user is valid for")
+	kubeconfigAddUserCmd.Flags().StringVar(&userOutput, "output", "", "Write a standalone kubeconfig for the user to this file instead of adding a context to the kubeconfig")
+	kubeconfigAddUserCmd.Flags().StringVar(&userClusterRole, "cluster-role", "", "Bind the user to this ClusterRole with a ClusterRoleBinding")
+	kubeconfigAddUserCmd.Flags().StringVar(&userRole, "role", "", "Bind the user to this Role with a RoleBinding in --namespace")
+	kubeconfigAddUserCmd.Flags().StringVar(&userNamespace, "namespace", "default", "The namespace of the RoleBinding created with --role")
+	kubeconfigCmd.AddCommand(kubeconfigAddUserCmd)
+	RootCmd.AddCommand(kubeconfigCmd)
+}
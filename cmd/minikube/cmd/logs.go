@@ -18,21 +18,36 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/docker/machine/libmachine/host"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
 var (
-	follow bool
+	follow    bool
+	component string
+	logLines  int
 )
 
+// componentAudit selects the apiserver audit log with --component
+const componentAudit = "audit"
+
+// containerComponents are the components whose container logs can be selected
+// with --component
+var containerComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd", "kube-proxy", "kube-addon-manager"}
+
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs",
@@ -45,6 +60,28 @@ var logsCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		defer api.Close()
+
+		if component != "" {
+			h, err := api.Load(config.GetMachineName())
+			if err != nil {
+				glog.Exitf("Error getting host: %s", err)
+			}
+			runner, err := machine.GetCommandRunner(h)
+			if err != nil {
+				glog.Exitf("Error getting command runner: %s", err)
+			}
+			if component == componentAudit {
+				err = getAuditLogsTo(h, runner, os.Stdout)
+			} else {
+				err = getContainerLogsTo(h, runner, component, os.Stdout)
+			}
+			if err != nil {
+				log.Println("Error getting component logs:", err)
+				cmdUtil.MaybeReportErrorAndExit(err)
+			}
+			return
+		}
+
 		clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
 		if err != nil {
 			glog.Exitf("Error getting cluster bootstrapper: %s", err)
@@ -58,7 +95,57 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// getContainerLogsTo writes the logs of the most recent container of a
+// control plane component to out
+func getContainerLogsTo(h *host.Host, runner bootstrapper.CommandRunner, name string, out io.Writer) error {
+	valid := false
+	for _, c := range containerComponents {
+		valid = valid || c == name
+	}
+	if !valid {
+		return fmt.Errorf("unknown component %q, valid components are: %s", name, strings.Join(append([]string{componentAudit}, containerComponents...), ", "))
+	}
+
+	cr, err := getContainerRuntime(h)
+	if err != nil {
+		return errors.Wrap(err, "getting container runtime")
+	}
+	ids, err := cr.ListContainers(name)
+	if err != nil {
+		return errors.Wrapf(err, "listing %s containers", name)
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("no %s container found, is the cluster running with the kubeadm bootstrapper?", name)
+	}
+
+	logsCommand := cr.ContainerLogs(ids[0], logLines, follow)
+	if follow {
+		return runner.CombinedOutputTo(logsCommand, out)
+	}
+	logs, err := runner.CombinedOutput(logsCommand)
+	if err != nil {
+		return errors.Wrapf(err, "getting %s logs", name)
+	}
+	_, err = fmt.Fprint(out, logs)
+	return err
+}
+
+// getAuditLogsTo writes the audit log of the apiserver to out. localkube writes
+// it to a file, the kubeadm apiserver to its container log.
+func getAuditLogsTo(h *host.Host, runner bootstrapper.CommandRunner, out io.Writer) error {
+	if viper.GetString(cmdcfg.Bootstrapper) == bootstrapper.BootstrapperTypeLocalkube {
+		return bootstrapper.GetAuditLogsTo(runner, follow, out)
+	}
+	w := &bootstrapper.AuditEventWriter{Out: out}
+	if err := getContainerLogsTo(h, runner, "kube-apiserver", w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
 func init() {
+	logsCmd.Flags().StringVar(&component, "component", "", fmt.Sprintf("Show the logs of a single component instead of the cluster. One of: %s", strings.Join(append([]string{componentAudit}, containerComponents...), ", ")))
+	logsCmd.Flags().IntVarP(&logLines, "length", "n", 0, "Number of lines to show from the end of the logs of a --component container, 0 shows all of them")
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
 	RootCmd.AddCommand(logsCmd)
 }
@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	kcfg "k8s.io/minikube/pkg/util/kubeconfig"
+)
+
+// resetCmd represents the reset command
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Wipes the state of the local kubernetes cluster and starts a new one in the same VM",
+	Long: `Removes everything stored in the local kubernetes cluster, including its etcd data, and starts
+a new cluster with the configuration of the profile. The VM, the certificates, the image cache and
+the enabled addons are kept, so this is much faster than deleting and starting minikube again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		ms, err := cluster.GetHostStatus(api)
+		if err != nil {
+			glog.Errorln("Error getting machine status:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if ms != state.Running.String() {
+			fmt.Fprintln(os.Stderr, "minikube is not running, start it before resetting the cluster.")
+			os.Exit(1)
+		}
+
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err != nil {
+			glog.Exitf("Error loading profile config: %s", err)
+		}
+		k8s := cc.KubernetesConfig
+		ip, err := cluster.GetHostDriverIP(api)
+		if err != nil {
+			glog.Exitf("Error getting VM IP address: %s", err)
+		}
+		k8s.NodeIP = ip.String()
+
+		clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+		if err != nil {
+			glog.Exitf("Error getting cluster bootstrapper: %s", err)
+		}
+
+		fmt.Println("Removing the cluster state...")
+		if err := clusterBootstrapper.ResetCluster(k8s); err != nil {
+			glog.Errorln("Error resetting cluster:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		// The reset removes the manifests of the addons along with the rest
+		fmt.Println("Moving files into cluster...")
+		if err := clusterBootstrapper.UpdateCluster(k8s); err != nil {
+			glog.Errorln("Error updating cluster:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Setting up certs...")
+		if err := clusterBootstrapper.SetupCerts(k8s); err != nil {
+			glog.Errorln("Error configuring authentication:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Starting cluster components...")
+		if err := clusterBootstrapper.StartCluster(k8s); err != nil {
+			glog.Errorln("Error starting cluster:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		port := bootstrapper.GetAPIServerPort(k8s)
+		if _, err := kcfg.UpdateKubeconfigIP(ip, port, constants.KubeconfigPath, cfg.GetMachineName()); err != nil {
+			glog.Warningf("Unable to update kubeconfig: %s", err)
+			fmt.Fprintln(os.Stderr, "The cluster was reset, but kubeconfig couldn't be updated. Run 'minikube update-context' to fix it.")
+			os.Exit(1)
+		}
+		fmt.Println("The local Kubernetes cluster has been reset.")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(resetCmd)
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/log"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -38,6 +39,8 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper/localkube"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/notify"
 )
 
@@ -188,3 +191,17 @@ func GetClusterBootstrapper(api libmachine.API, bootstrapperName string) (bootst
 
 	return b, nil
 }
+
+// getContainerRuntime returns the container runtime of the profile, running its
+// commands on h
+func getContainerRuntime(h *host.Host) (cruntime.Manager, error) {
+	cc, err := loadConfigFromFile(viper.GetString(config.MachineProfile))
+	if err != nil {
+		glog.Warningf("Unable to load profile config, assuming the default container runtime: %s", err)
+	}
+	runner, err := machine.GetCommandRunner(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting command runner")
+	}
+	return cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+}
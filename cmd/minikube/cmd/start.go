@@ -39,6 +39,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/cluster"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/kubernetes_versions"
 	"k8s.io/minikube/pkg/minikube/machine"
 	pkgutil "k8s.io/minikube/pkg/util"
@@ -59,13 +60,21 @@ const (
 	hostOnlyCIDR          = "host-only-cidr"
 	containerRuntime      = "container-runtime"
 	networkPlugin         = "network-plugin"
+	cni                   = "cni"
+	auditPolicy           = "audit-policy"
+	sharedCA              = "shared-ca"
+	force                 = "force"
 	hypervVirtualSwitch   = "hyperv-virtual-switch"
 	kvmNetwork            = "kvm-network"
 	keepContext           = "keep-context"
 	createMount           = "mount"
 	featureGates          = "feature-gates"
 	apiServerName         = "apiserver-name"
+	apiServerPort         = "apiserver-port"
+	serviceCIDR           = "service-cluster-ip-range"
+	podCIDR               = "pod-network-cidr"
 	dnsDomain             = "dns-domain"
+	nodeResourcePreset    = "node-resource-preset"
 	mountString           = "mount-string"
 	disableDriverMounts   = "disable-driver-mounts"
 	cacheImages           = "cache-images"
@@ -80,6 +89,7 @@ var (
 	apiServerNames   []string
 	apiServerIPs     []net.IP
 	extraOptions     pkgutil.ExtraOptionSlice
+	cpFiles          []string
 )
 
 // startCmd represents the start command
@@ -100,8 +110,69 @@ func runStart(cmd *cobra.Command, args []string) {
 	k8sVersion := viper.GetString(kubernetesVersion)
 	clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
 
+	selectedCNI := viper.GetString(cni)
+	if err := bootstrapper.ValidateCNI(selectedCNI); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if selectedCNI != "" && viper.GetString(networkPlugin) != "" && viper.GetString(networkPlugin) != "cni" {
+		fmt.Fprintf(os.Stderr, "--cni=%s requires the cni network plugin, but --network-plugin=%s was given\n", selectedCNI, viper.GetString(networkPlugin))
+		os.Exit(1)
+	}
+
+	validateExtraOptions(k8sVersion)
+	validateFeatureGates(k8sVersion)
+
+	if r := viper.GetString(containerRuntime); !cruntime.IsSupported(r) {
+		fmt.Printf("minikube doesn't manage the %s container runtime, it is only passed to the kubelet. Cached images won't be loaded into it.\n", r)
+	}
+
+	if err := pkgutil.ValidateNetworkCIDRs(viper.GetString(serviceCIDR), viper.GetString(podCIDR)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := bootstrapper.ValidateNodeResourcePreset(viper.GetString(nodeResourcePreset)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if viper.GetString(nodeResourcePreset) != bootstrapper.NodeResourcePresetNone && clusterBootstrapper == bootstrapper.BootstrapperTypeLocalkube {
+		fmt.Fprintf(os.Stderr, "Warning: --%s is only supported by the kubeadm bootstrapper, no resources are reserved with localkube.\n", nodeResourcePreset)
+	}
+
+	if port := viper.GetInt(apiServerPort); port <= 0 || port > 65535 {
+		fmt.Fprintf(os.Stderr, "--apiserver-port=%d is not a valid port\n", port)
+		os.Exit(1)
+	}
+
+	selectedAuditPolicy := viper.GetString(auditPolicy)
+	if err := bootstrapper.ValidateAuditPolicy(selectedAuditPolicy); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// Policy files are read again on every start, so remember where they are
+	// independently of the directory minikube was started from
+	if selectedAuditPolicy != "" && selectedAuditPolicy != bootstrapper.AuditPolicyMetadata {
+		p, err := filepath.Abs(selectedAuditPolicy)
+		if err != nil {
+			glog.Exitf("Error getting path of audit policy: %s", err)
+		}
+		selectedAuditPolicy = p
+	}
+
+	var controlPlaneFiles []bootstrapper.ControlPlaneFile
+	for _, s := range cpFiles {
+		f, err := bootstrapper.ParseControlPlaneFile(s)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		controlPlaneFiles = append(controlPlaneFiles, f)
+	}
+
 	if shouldCacheImages {
 		go machine.CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper)
+		go machine.CacheImages(bootstrapper.GetCNICachedImages(selectedCNI), constants.ImageCacheDir)
 	}
 	api, err := machine.NewAPIClient()
 	if err != nil {
@@ -114,6 +185,7 @@ func runStart(cmd *cobra.Command, args []string) {
 	if err != nil {
 		glog.Exitf("checking if machine exists: %s", err)
 	}
+	legacyCerts := exists && migrateLegacyCerts()
 
 	diskSize := viper.GetString(humanReadableDiskSize)
 	diskSizeMB := pkgutil.CalculateDiskSizeInMB(diskSize)
@@ -148,6 +220,8 @@ func runStart(cmd *cobra.Command, args []string) {
 		Downloader:          pkgutil.DefaultDownloader{},
 		DisableDriverMounts: viper.GetBool(disableDriverMounts),
 		UUID:                viper.GetString(uuid),
+		ContainerRuntime:    viper.GetString(containerRuntime),
+		ServiceCIDR:         viper.GetString(serviceCIDR),
 	}
 
 	fmt.Printf("Starting local Kubernetes %s cluster...\n", viper.GetString(kubernetesVersion))
@@ -172,6 +246,10 @@ func runStart(cmd *cobra.Command, args []string) {
 		glog.Errorln("Error getting VM IP address: ", err)
 		cmdutil.MaybeReportErrorAndExit(err)
 	}
+	if err := pkgutil.ValidateNodeIPFamily(ip, viper.GetString(serviceCIDR), viper.GetString(podCIDR)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	selectedKubernetesVersion := viper.GetString(kubernetesVersion)
 
@@ -198,6 +276,12 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// The kubelet of the none driver runs on the host, whose resources aren't those of the flags
+	nodeMemory, nodeCPUs := config.Memory, config.CPUs
+	if config.VMDriver == constants.DriverNone {
+		nodeMemory, nodeCPUs = 0, 0
+	}
+
 	kubernetesConfig := bootstrapper.KubernetesConfig{
 		KubernetesVersion:      selectedKubernetesVersion,
 		NodeIP:                 ip,
@@ -205,12 +289,22 @@ func runStart(cmd *cobra.Command, args []string) {
 		APIServerName:          viper.GetString(apiServerName),
 		APIServerNames:         apiServerNames,
 		APIServerIPs:           apiServerIPs,
+		APIServerPort:          viper.GetInt(apiServerPort),
 		DNSDomain:              viper.GetString(dnsDomain),
 		FeatureGates:           viper.GetString(featureGates),
 		ContainerRuntime:       viper.GetString(containerRuntime),
 		NetworkPlugin:          viper.GetString(networkPlugin),
-		ServiceCIDR:            pkgutil.DefaultServiceCIDR,
+		CNI:                    selectedCNI,
+		AuditPolicy:            selectedAuditPolicy,
+		ControlPlaneFiles:      controlPlaneFiles,
+		SharedCA:               viper.GetBool(sharedCA) || cc.KubernetesConfig.SharedCA || legacyCerts,
+		ServiceCIDR:            viper.GetString(serviceCIDR),
+		PodCIDR:                viper.GetString(podCIDR),
+		NodeResourcePreset:     viper.GetString(nodeResourcePreset),
+		NodeMemory:             nodeMemory,
+		NodeCPUs:               nodeCPUs,
 		ExtraOptions:           extraOptions,
+		ForceExtraOptions:      viper.GetBool(force),
 		ShouldLoadCachedImages: shouldCacheImages,
 	}
 
@@ -235,11 +329,22 @@ func runStart(cmd *cobra.Command, args []string) {
 		cmdutil.MaybeReportErrorAndExit(err)
 	}
 
+	// The VM may have a new DHCP lease, check before the certs are signed for it
+	ipChanged := false
+	if exists {
+		if ipChanged, err = bootstrapper.NodeIPChanged(kubernetesConfig); err != nil {
+			glog.Warningf("Unable to check the apiserver cert for IP %s: %s", ip, err)
+		} else if ipChanged {
+			fmt.Printf("The VM IP changed to %s, updating the cluster...\n", ip)
+		}
+	}
+
 	fmt.Println("Setting up certs...")
 	if err := k8sBootstrapper.SetupCerts(kubernetesConfig); err != nil {
 		glog.Errorln("Error configuring authentication: ", err)
 		cmdutil.MaybeReportErrorAndExit(err)
 	}
+	warnExpiringCerts(kubernetesConfig)
 
 	fmt.Println("Connecting to cluster...")
 	kubeHost, err := host.Driver.GetURL()
@@ -247,7 +352,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		glog.Errorln("Error connecting to cluster: ", err)
 	}
 	kubeHost = strings.Replace(kubeHost, "tcp://", "https://", -1)
-	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(pkgutil.APIServerPort), -1)
+	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(bootstrapper.GetAPIServerPort(kubernetesConfig)), -1)
 
 	fmt.Println("Setting up kubeconfig...")
 	// setup kubeconfig
@@ -257,13 +362,20 @@ func runStart(cmd *cobra.Command, args []string) {
 	kubeCfgSetup := &kubeconfig.KubeConfigSetup{
 		ClusterName:          cfg.GetMachineName(),
 		ClusterServerAddress: kubeHost,
-		ClientCertificate:    constants.MakeMiniPath("client.crt"),
-		ClientKey:            constants.MakeMiniPath("client.key"),
-		CertificateAuthority: constants.MakeMiniPath("ca.crt"),
+		ClientCertificate:    bootstrapper.CertPath(kubernetesConfig, "client.crt"),
+		ClientKey:            bootstrapper.CertPath(kubernetesConfig, "client.key"),
+		CertificateAuthority: bootstrapper.CertPath(kubernetesConfig, "ca.crt"),
 		KeepContext:          viper.GetBool(keepContext),
 	}
 	kubeCfgSetup.SetKubeConfigFile(kubeConfigFile)
 
+	if ipChanged {
+		// SetupKubeConfig only updates the entry of the machine
+		if _, err := kubeconfig.UpdateKubeconfigIP(net.ParseIP(ip), bootstrapper.GetAPIServerPort(kubernetesConfig), kubeConfigFile, cfg.GetMachineName()); err != nil {
+			glog.Warningf("Unable to update the kubeconfig entries for the new IP: %s", err)
+		}
+	}
+
 	if err := kubeconfig.SetupKubeConfig(kubeCfgSetup); err != nil {
 		glog.Errorln("Error setting up kubeconfig: ", err)
 		cmdutil.MaybeReportErrorAndExit(err)
@@ -276,6 +388,12 @@ func runStart(cmd *cobra.Command, args []string) {
 			glog.Errorln("Error starting cluster: ", err)
 			cmdutil.MaybeReportErrorAndExit(err)
 		}
+	} else if ipChanged {
+		// SetupCerts already signed the apiserver cert for the new IP
+		if err := k8sBootstrapper.UpdateNodeIP(kubernetesConfig); err != nil {
+			glog.Errorln("Error updating cluster for the new IP: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
 	} else {
 		if err := k8sBootstrapper.RestartCluster(kubernetesConfig); err != nil {
 			glog.Errorln("Error restarting cluster: ", err)
@@ -347,12 +465,59 @@ This can also be done automatically by setting the env var CHANGE_MINIKUBE_NONE_
 	}
 
 	fmt.Println("Loading cached images from config file.")
-	err = LoadCachedImagesInConfigFile()
+	err = LoadCachedImagesInConfigFile(kubernetesConfig.ContainerRuntime)
 	if err != nil {
 		fmt.Println("Unable to load cached images from config file.")
 	}
 }
 
+// validateExtraOptions checks the --extra-config keys against the flags of the
+// requested Kubernetes version before any machine is created.
+func validateExtraOptions(k8sVersion string) {
+	if viper.GetBool(force) {
+		return
+	}
+	var v *semver.Version
+	if parsed, err := semver.Make(strings.TrimPrefix(k8sVersion, version.VersionPrefix)); err == nil {
+		v = &parsed
+	}
+	for _, e := range extraOptions {
+		warnings, err := pkgutil.ValidateExtraOption(e, v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --extra-config=%s: %s\n", e.String(), err)
+			os.Exit(1)
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+}
+
+// validateFeatureGates checks --feature-gates against the gates of the
+// requested Kubernetes version.
+func validateFeatureGates(k8sVersion string) {
+	gates, err := pkgutil.ParseFeatureGates(viper.GetString(featureGates))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --feature-gates: %s\n", err)
+		os.Exit(1)
+	}
+	if viper.GetBool(force) {
+		return
+	}
+	var v *semver.Version
+	if parsed, err := semver.Make(strings.TrimPrefix(k8sVersion, version.VersionPrefix)); err == nil {
+		v = &parsed
+	}
+	warnings, err := pkgutil.ValidateFeatureGates(gates, v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --feature-gates: %s\n", err)
+		os.Exit(1)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+}
+
 func validateK8sVersion(version string) {
 	validVersion, err := kubernetes_versions.IsValidLocalkubeVersion(version, constants.KubernetesVersionGCSURL)
 	if err != nil {
@@ -367,6 +532,7 @@ func validateK8sVersion(version string) {
 }
 
 func init() {
+	startCmd.Flags().Bool(sharedCA, false, "Sign the certificates of this profile with the CA in the minikube home, which is shared with other profiles, instead of a CA of its own. The choice is saved with the profile")
 	startCmd.Flags().Bool(keepContext, constants.DefaultKeepContext, "This will keep the existing kubectl context and will create a minikube context.")
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":"+constants.DefaultMountEndpoint, "The argument to pass the minikube mount command on start")
@@ -385,6 +551,7 @@ func init() {
 	startCmd.Flags().StringArrayVar(&dockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&dockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The apiserver name which is used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().Int(apiServerPort, pkgutil.APIServerPort, "The port the apiserver listens on, in the VM and in the kubeconfig")
 	startCmd.Flags().StringArrayVar(&apiServerNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().IPSliceVar(&apiServerIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for localkube/kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().String(dnsDomain, constants.ClusterDNSDomain, "The cluster dns domain name used in the kubernetes cluster")
@@ -393,8 +560,15 @@ func init() {
 	startCmd.Flags().String(kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version that the minikube VM will use (ex: v1.2.3) \n OR a URI which contains a localkube binary (ex: https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64)")
 	startCmd.Flags().String(containerRuntime, "", "The container runtime to be used")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin")
-	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	startCmd.Flags().String(serviceCIDR, pkgutil.DefaultServiceCIDR, "The range service IPs are allocated from, IPv4 or IPv6. The cluster DNS is at the 10th IP of the range")
+	startCmd.Flags().String(podCIDR, "", fmt.Sprintf("The range pod IPs are allocated from, IPv4 or IPv6. Defaults to %s when --cni is set", constants.DefaultPodCIDR))
+	startCmd.Flags().String(cni, "", fmt.Sprintf("CNI to deploy, which also configures the kubelet to use the cni network plugin. One of: %v", bootstrapper.SupportedCNIs))
+	startCmd.Flags().String(auditPolicy, "", fmt.Sprintf("Enables apiserver audit logging with the given audit policy file, or %q for a built-in policy that logs the metadata of every request", bootstrapper.AuditPolicyMetadata))
+	startCmd.Flags().StringArrayVar(&cpFiles, "control-plane-file", nil, fmt.Sprintf("A file to copy into the VM and mount into a control plane component, e.g. for admission or encryption configs. (format: component:hostpath:vmpath, components: %v)", bootstrapper.ControlPlaneComponents))
+	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features. Prefix a key with a component to only set it for that component, e.g. kubelet:DevicePlugins=true")
+	startCmd.Flags().String(nodeResourcePreset, bootstrapper.NodeResourcePresetNone, fmt.Sprintf("The resources the kubelet reserves for the system and Kubernetes, which are not allocatable to pods (kubeadm bootstrapper only). One of %v, balanced reserves a share of --memory and --cpus", bootstrapper.NodeResourcePresets))
 	startCmd.Flags().Bool(cacheImages, true, "If true, cache docker images for the current bootstrapper and load them into the machine.")
+	startCmd.Flags().Bool(force, false, "Skip the validation of --extra-config keys against the flags of each component, and of --feature-gates against the known feature gates")
 	startCmd.Flags().Var(&extraOptions, "extra-config",
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
@@ -472,3 +646,13 @@ func loadConfigFromFile(profile string) (cluster.Config, error) {
 	}
 	return cc, nil
 }
+
+// getAPIServerPort returns the apiserver port of the profile, or the default
+// port if the profile has no config
+func getAPIServerPort() int {
+	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		glog.Warningf("Unable to load profile config, using the default apiserver port: %s", err)
+	}
+	return bootstrapper.GetAPIServerPort(cc.KubernetesConfig)
+}
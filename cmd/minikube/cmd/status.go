@@ -17,29 +17,45 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/template"
 
+	"github.com/blang/semver"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/kubeconfig"
+	"k8s.io/minikube/pkg/version"
 )
 
-var statusFormat string
+var (
+	statusFormat string
+	statusOutput string
+)
 
 type Status struct {
 	MinikubeStatus   string
 	ClusterStatus    string
 	KubeconfigStatus string
+	// ContainerRuntime is the name of the container runtime the cluster uses
+	ContainerRuntime       string `json:",omitempty"`
+	ContainerRuntimeStatus string `json:",omitempty"`
+	// FeatureGates are the feature gates each component is started with
+	FeatureGates map[string]map[string]bool `json:",omitempty"`
+	// Components are the state of the components, if the bootstrapper reports them
+	Components []bootstrapper.ComponentStatus `json:",omitempty"`
 }
 
 const internalErrorCode = -1
@@ -58,6 +74,11 @@ var statusCmd = &cobra.Command{
 	Exit status contains the status of minikube's VM, cluster and kubernetes encoded on it's bits in this order from right to left.
 	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if statusOutput != "text" && statusOutput != "json" {
+			fmt.Fprintf(os.Stderr, "Invalid --output=%s, valid outputs are text and json\n", statusOutput)
+			os.Exit(internalErrorCode)
+		}
+
 		var returnCode = 0
 		api, err := machine.NewAPIClient()
 		if err != nil {
@@ -74,7 +95,25 @@ var statusCmd = &cobra.Command{
 
 		cs := state.None.String()
 		ks := state.None.String()
+		var rs, rname string
+		var components []bootstrapper.ComponentStatus
 		if ms == state.Running.String() {
+			h, err := api.Load(config.GetMachineName())
+			if err != nil {
+				glog.Errorln("Error getting host:", err)
+				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
+			}
+			// Runtimes minikube doesn't manage have no status
+			if cr, err := getContainerRuntime(h); err != nil {
+				glog.Warningf("Unable to get the container runtime: %s", err)
+			} else {
+				rname, rs = cr.Name(), state.Running.String()
+				if !cr.Active() {
+					rs = state.Stopped.String()
+					returnCode |= clusterNotRunningStatusFlag
+				}
+			}
+
 			clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
 			if err != nil {
 				glog.Errorf("Error getting cluster bootstrapper: %s", err)
@@ -86,6 +125,8 @@ var statusCmd = &cobra.Command{
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
 			} else if cs != state.Running.String() {
 				returnCode |= clusterNotRunningStatusFlag
+			} else if components, err = clusterBootstrapper.GetComponentStatus(); err != nil {
+				glog.Warningf("Unable to get the status of the components: %s", err)
 			}
 
 			ip, err := cluster.GetHostDriverIP(api)
@@ -93,23 +134,42 @@ var statusCmd = &cobra.Command{
 				glog.Errorln("Error host driver ip status:", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
 			}
-			kstatus, err := kubeconfig.GetKubeConfigStatus(ip, cmdUtil.GetKubeConfigPath(), config.GetMachineName())
+			port := getAPIServerPort()
+			kstatus, err := kubeconfig.GetKubeConfigStatus(ip, port, cmdUtil.GetKubeConfigPath(), config.GetMachineName())
 			if err != nil {
 				glog.Errorln("Error kubeconfig status:", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
 			}
 			if kstatus {
-				ks = "Correctly Configured: pointing to minikube-vm at " + ip.String()
+				ks = fmt.Sprintf("Correctly Configured: pointing to minikube-vm at %s:%d", ip, port)
 			} else {
 				ks = "Misconfigured: pointing to stale minikube-vm." +
-					"\nTo fix the kubectl context, run minikube update-context"
+					"\nTo fix the kubectl context and the apiserver certificate, run minikube update-context"
 				returnCode |= k8sNotRunningStatusFlag
 			}
 		} else {
 			returnCode |= minikubeNotRunningStatusFlag
 		}
 
-		status := Status{ms, cs, ks}
+		status := Status{
+			MinikubeStatus:         ms,
+			ClusterStatus:          cs,
+			KubeconfigStatus:       ks,
+			ContainerRuntime:       rname,
+			ContainerRuntimeStatus: rs,
+			FeatureGates:           activeFeatureGates(),
+			Components:             components,
+		}
+
+		if statusOutput == "json" {
+			b, err := json.MarshalIndent(status, "", "    ")
+			if err != nil {
+				glog.Errorln("Error encoding status:", err)
+				os.Exit(internalErrorCode)
+			}
+			fmt.Println(string(b))
+			os.Exit(returnCode)
+		}
 
 		tmpl, err := template.New("status").Parse(statusFormat)
 		if err != nil {
@@ -126,9 +186,40 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// activeFeatureGates returns the feature gates of each component of the profile,
+// or nil if it has none
+func activeFeatureGates() map[string]map[string]bool {
+	cc, err := loadConfigFromFile(viper.GetString(config.MachineProfile))
+	if err != nil {
+		glog.Warningf("Unable to load profile config: %s", err)
+		return nil
+	}
+	gates, err := util.ParseFeatureGates(cc.KubernetesConfig.FeatureGates)
+	if err != nil {
+		glog.Warningf("Unable to parse feature gates: %s", err)
+		return nil
+	}
+	var v *semver.Version
+	if parsed, err := semver.Make(strings.TrimPrefix(cc.KubernetesConfig.KubernetesVersion, version.VersionPrefix)); err == nil {
+		v = &parsed
+	}
+
+	active := map[string]map[string]bool{}
+	for _, component := range util.FeatureGateComponents {
+		if g := gates.ForComponent(component, v); len(g) > 0 {
+			active[component] = g
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	return active
+}
+
 func init() {
 	statusCmd.Flags().StringVar(&statusFormat, "format", constants.DefaultStatusFormat,
 		`Go template format string for the status output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#Status`)
+	statusCmd.Flags().StringVar(&statusOutput, "output", "text", "The output of the status, text (formatted with --format) or json")
 	RootCmd.AddCommand(statusCmd)
 }
@@ -20,9 +20,15 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
@@ -30,8 +36,9 @@ import (
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stops a running local kubernetes cluster",
-	Long: `Stops a local kubernetes cluster running in Virtualbox. This command stops the VM
-itself, leaving all files intact. The cluster can be started again with the "start" command.`,
+	Long: `Stops a local kubernetes cluster running in Virtualbox. This command shuts Kubernetes
+down gracefully and stops the VM itself, leaving all files intact. The cluster can be started
+again with the "start" command.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Stopping local Kubernetes cluster...")
 		api, err := machine.NewAPIClient()
@@ -41,6 +48,7 @@ itself, leaving all files intact. The cluster can be started again with the "sta
 		}
 		defer api.Close()
 
+		stopCluster(api)
 		if err = cluster.StopHost(api); err != nil {
 			fmt.Println("Error stopping machine: ", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
@@ -53,6 +61,29 @@ itself, leaving all files intact. The cluster can be started again with the "sta
 	},
 }
 
+// stopCluster shuts Kubernetes down before the machine is stopped. The machine
+// is stopped regardless, so errors are only reported.
+func stopCluster(api libmachine.API) {
+	ms, err := cluster.GetHostStatus(api)
+	if err != nil || ms != state.Running.String() {
+		return
+	}
+	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		glog.Warningf("Not stopping Kubernetes, unable to load the profile config: %s", err)
+		return
+	}
+	clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		glog.Warningf("Not stopping Kubernetes, unable to get the cluster bootstrapper: %s", err)
+		return
+	}
+	fmt.Println("Stopping Kubernetes...")
+	if err := clusterBootstrapper.StopCluster(cc.KubernetesConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Error stopping Kubernetes, stopping the machine anyway: %s\n", err)
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(stopCmd)
 }
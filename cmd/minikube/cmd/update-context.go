@@ -18,11 +18,17 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -33,9 +39,10 @@ import (
 // updateContextCmd represents the update-context command
 var updateContextCmd = &cobra.Command{
 	Use:   "update-context",
-	Short: "Verify the IP address of the running cluster in kubeconfig.",
-	Long: `Retrieves the IP address of the running cluster, checks it
-			with IP in kubeconfig, and corrects kubeconfig if incorrect.`,
+	Short: "Verify the IP address and port of the running cluster in kubeconfig.",
+	Long: `Retrieves the IP address and apiserver port of the running cluster, checks them
+			with the server in kubeconfig, and corrects kubeconfig if incorrect. If the VM got a
+			new IP address, the apiserver certificate is signed again for it and the apiserver restarted.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		api, err := machine.NewAPIClient()
 		if err != nil {
@@ -48,20 +55,55 @@ var updateContextCmd = &cobra.Command{
 			glog.Errorln("Error host driver ip status:", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
 		}
-		kstatus, err := kcfg.UpdateKubeconfigIP(ip, constants.KubeconfigPath, config.GetMachineName())
+		if err := updateNodeIP(api, ip); err != nil {
+			glog.Errorln("Error updating cluster for the new IP:", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		port := getAPIServerPort()
+		kstatus, err := kcfg.UpdateKubeconfigIP(ip, port, constants.KubeconfigPath, config.GetMachineName())
 		if err != nil {
 			glog.Errorln("Error kubeconfig status:", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
 		}
 		if kstatus {
-			fmt.Println("Reconfigured kubeconfig IP, now pointing at " + ip.String())
+			fmt.Printf("Reconfigured kubeconfig IP, now pointing at %s:%d\n", ip, port)
 		} else {
-			fmt.Println("Kubeconfig IP correctly configured, pointing at " + ip.String())
+			fmt.Printf("Kubeconfig IP correctly configured, pointing at %s:%d\n", ip, port)
 		}
 
 	},
 }
 
+// updateNodeIP reconfigures the cluster if the apiserver cert wasn't signed for
+// the IP of the VM, and saves the IP in the profile config.
+func updateNodeIP(api libmachine.API, ip net.IP) error {
+	cc, err := loadConfigFromFile(viper.GetString(config.MachineProfile))
+	if err != nil {
+		glog.Warningf("Unable to load profile config, not checking the certs: %s", err)
+		return nil
+	}
+	k8s := cc.KubernetesConfig
+	k8s.NodeIP = ip.String()
+	changed, err := bootstrapper.NodeIPChanged(k8s)
+	if err != nil {
+		return errors.Wrap(err, "checking apiserver cert")
+	}
+	if !changed {
+		return nil
+	}
+
+	fmt.Printf("The VM IP changed to %s, updating the apiserver certificate...\n", ip)
+	clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		return errors.Wrap(err, "getting cluster bootstrapper")
+	}
+	if err := clusterBootstrapper.UpdateNodeIP(k8s); err != nil {
+		return err
+	}
+	cc.KubernetesConfig = k8s
+	return saveConfig(cc)
+}
+
 func init() {
 	RootCmd.AddCommand(updateContextCmd)
 }
@@ -20,11 +20,24 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/golang/glog"
 	"k8s.io/minikube/pkg/localkube"
 )
 
+var (
+	pvDir         = flag.String("pv-dir", defaultPVDir(), fmt.Sprintf("The directory to create PV-backing directories in. Can also be set with the %s environment variable.", localkube.PVDirEnv))
+	usageInterval = flag.Duration("usage-interval", time.Minute, fmt.Sprintf("How often to update the %s annotation of PVs with their disk usage. 0 disables it.", localkube.UsageAnnotation))
+)
+
+func defaultPVDir() string {
+	if dir := os.Getenv(localkube.PVDirEnv); dir != "" {
+		return dir
+	}
+	return localkube.DefaultPVDir
+}
+
 func main() {
 	// Glog requires that /tmp exists.
 	if err := os.MkdirAll("/tmp", 0755); err != nil {
@@ -33,7 +46,7 @@ func main() {
 	}
 	flag.Parse()
 
-	if err := localkube.StartStorageProvisioner(); err != nil {
+	if err := localkube.StartStorageProvisioner(*pvDir, *usageInterval); err != nil {
 		glog.Exit(err)
 	}
 
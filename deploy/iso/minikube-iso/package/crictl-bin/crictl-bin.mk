@@ -0,0 +1,22 @@
+################################################################################
+#
+# crictl-bin
+#
+################################################################################
+
+CRICTL_BIN_VERSION = v1.0.0-beta.0
+CRICTL_BIN_SITE = https://github.com/kubernetes-incubator/cri-tools/releases/download/$(CRICTL_BIN_VERSION)
+CRICTL_BIN_SOURCE = crictl-$(CRICTL_BIN_VERSION)-linux-amd64.tar.gz
+
+# The tarball only contains the crictl binary, without a top-level directory to strip
+define CRICTL_BIN_EXTRACT_CMDS
+	tar -C $(@D) -xzf $(BR2_DL_DIR)/$(CRICTL_BIN_SOURCE)
+endef
+
+define CRICTL_BIN_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/crictl \
+		$(TARGET_DIR)/usr/bin/crictl
+endef
+
+$(eval $(generic-package))
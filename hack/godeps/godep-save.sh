@@ -48,5 +48,6 @@ git checkout -- ${MINIKUBE_ROOT}/vendor/golang.org/x/sys/windows
 pushd ${MINIKUBE_ROOT} >/dev/null
     git apply ${MINIKUBE_ROOT}/hack/tpr-patch.diff
     git apply ${MINIKUBE_ROOT}/hack/kube-proxy-patch.diff
+    git apply ${MINIKUBE_ROOT}/hack/localkube-stop-patch.diff
 popd >/dev/null
 
@@ -27,18 +27,19 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/net"
 	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 )
 
 const driverName = "none"
-const dockerkillcmd = `docker rm $(docker kill $(docker ps -a --filter="name=k8s_" --format="{{.ID}}"))`
-const dockerstopcmd = `docker stop $(docker ps -a --filter="name=k8s_" --format="{{.ID}}")`
 
 // none Driver is a driver designed to run localkube w/o a VM
 type Driver struct {
 	*drivers.BaseDriver
 	*pkgdrivers.CommonDriver
-	URL string
+	URL              string
+	ContainerRuntime string
 }
 
 func NewDriver(hostName, storePath string) *Driver {
@@ -50,13 +51,21 @@ func NewDriver(hostName, storePath string) *Driver {
 	}
 }
 
+// runtime returns the container runtime the cluster on this machine runs on
+func (d *Driver) runtime() (cruntime.Manager, error) {
+	return cruntime.New(cruntime.Config{Type: d.ContainerRuntime, Runner: &bootstrapper.ExecRunner{}})
+}
+
 // PreCreateCheck checks for correct priviledges and dependencies
 func (d *Driver) PreCreateCheck() error {
-	// check that docker is on path
-	_, err := exec.LookPath("docker")
+	cr, err := d.runtime()
 	if err != nil {
-		return errors.Wrap(err, "docker cannot be found on the path for this machine. "+
-			"A docker installation is a requirement for using the none driver")
+		return err
+	}
+	// check that the container runtime is on path
+	if err := cr.Available(); err != nil {
+		return errors.Wrapf(err, "%s cannot be found on the path for this machine. "+
+			"A %s installation is a requirement for using the none driver", cr.Name(), cr.Name())
 	}
 
 	return nil
@@ -145,7 +154,11 @@ func (d *Driver) Remove() error {
 		return errors.Wrap(err, "stopping minikube")
 	}
 
-	runCommand(dockerkillcmd, false)
+	if cr, err := d.runtime(); err == nil {
+		if ids, err := cr.ListContainers(""); err == nil {
+			cr.KillContainers(ids)
+		}
+	}
 
 	return nil
 }
@@ -177,7 +190,17 @@ func (d *Driver) Start() error {
 	return nil
 }
 
+// Stop shuts Kubernetes down the same way the bootstrappers do before a VM is stopped
 func (d *Driver) Stop() error {
+	cr, err := d.runtime()
+	if err != nil {
+		cr = nil
+	}
+	return bootstrapper.StopKubernetes(&bootstrapper.ExecRunner{}, cr, d.stopServices, bootstrapper.StopTimeout)
+}
+
+// stopServices stops localkube and the kubelet and waits for them to exit
+func (d *Driver) stopServices() error {
 	var stopcmd = fmt.Sprintf("if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; "+`then
 for svc in "localkube" "kubelet"; do
 	sudo systemctl stop "$svc".service || true
@@ -196,11 +219,9 @@ fi
 			return err
 		}
 		if s != state.Running {
-			break
+			return nil
 		}
 	}
-	runCommand(dockerstopcmd, false)
-	return nil
 }
 
 func (d *Driver) RunSSHCommandFromDriver() error {
@@ -23,8 +23,6 @@ import (
 
 	"k8s.io/minikube/pkg/util"
 
-	"github.com/coreos/etcd/embed"
-
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 
@@ -34,10 +32,10 @@ import (
 )
 
 func (lk LocalkubeServer) NewAPIServer() Server {
-	return NewSimpleServer("apiserver", serverInterval, StartAPIServer(lk), readyFunc(lk))
+	return NewSimpleServer(APIServerName, serverInterval, StartAPIServer(lk), readyFunc(lk))
 }
 
-func StartAPIServer(lk LocalkubeServer) func() error {
+func StartAPIServer(lk LocalkubeServer) func(stop <-chan struct{}) error {
 	config := options.NewServerRunOptions()
 
 	config.SecureServing.BindAddress = lk.APIServerAddress
@@ -51,10 +49,12 @@ func StartAPIServer(lk LocalkubeServer) func() error {
 	config.SecureServing.ServerCert.CertKey.CertFile = lk.GetPublicKeyCertPath()
 	config.SecureServing.ServerCert.CertKey.KeyFile = lk.GetPrivateKeyCertPath()
 	config.Admission.PluginNames = util.DefaultAdmissionControllers
-	// use localkube etcd
-
-	config.Etcd.StorageConfig.ServerList = []string{embed.DefaultListenClientURLs}
+	// use localkube etcd, unless --etcd-servers is set
+	config.Etcd.StorageConfig.ServerList = lk.GetEtcdServers()
 	config.Etcd.StorageConfig.Type = storagebackend.StorageTypeETCD3
+	config.Etcd.StorageConfig.CAFile = lk.EtcdCAFile
+	config.Etcd.StorageConfig.CertFile = lk.EtcdCertFile
+	config.Etcd.StorageConfig.KeyFile = lk.EtcdKeyFile
 
 	// set Service IP range
 	config.ServiceClusterIPRange = lk.ServiceClusterIPRange
@@ -88,8 +88,7 @@ func StartAPIServer(lk LocalkubeServer) func() error {
 
 	lk.SetExtraConfigForComponent("apiserver", &config)
 
-	return func() error {
-		stop := make(chan struct{})
+	return func(stop <-chan struct{}) error {
 		return apiserver.Run(config, stop)
 	}
 }
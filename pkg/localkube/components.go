@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/coreos/etcd/embed"
+)
+
+// The names of the servers localkube runs
+const (
+	APIServerName         = "apiserver"
+	ControllerManagerName = "controller-manager"
+	SchedulerName         = "scheduler"
+	KubeletName           = "kubelet"
+	ProxyName             = "proxy"
+)
+
+// Components are the components that can be passed to --disable-components
+var Components = []string{EtcdName, APIServerName, ControllerManagerName, SchedulerName, KubeletName, ProxyName}
+
+// IsEnabled returns whether the component wasn't disabled
+func (lk LocalkubeServer) IsEnabled(component string) bool {
+	for _, c := range lk.DisableComponents {
+		if c == component {
+			return false
+		}
+	}
+	return true
+}
+
+// GetEtcdServers returns the etcd the apiserver stores its data in
+func (lk LocalkubeServer) GetEtcdServers() []string {
+	if len(lk.EtcdServers) > 0 {
+		return lk.EtcdServers
+	}
+	return []string{embed.DefaultListenClientURLs}
+}
+
+// ValidateComponents checks that the disabled components are known, and that
+// the remaining ones can run together.
+func (lk LocalkubeServer) ValidateComponents() error {
+	for _, c := range lk.DisableComponents {
+		if c == "storage-provisioner" {
+			return fmt.Errorf("the storage-provisioner isn't run by localkube, disable the addon with: minikube addons disable storage-provisioner")
+		}
+		if !isComponent(c) {
+			return fmt.Errorf("unknown component %s in --disable-components, valid components are %v", c, Components)
+		}
+	}
+	// Every other component talks to the cluster through the apiserver
+	if !lk.IsEnabled(APIServerName) {
+		return fmt.Errorf("the %s can't be disabled", APIServerName)
+	}
+
+	if lk.IsEnabled(EtcdName) && len(lk.EtcdServers) > 0 {
+		return fmt.Errorf("--etcd-servers requires disabling the embedded etcd with --disable-components=%s", EtcdName)
+	}
+	if !lk.IsEnabled(EtcdName) && len(lk.EtcdServers) == 0 {
+		return fmt.Errorf("disabling %s requires --etcd-servers for the apiserver", EtcdName)
+	}
+	tls := false
+	for _, s := range lk.EtcdServers {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid etcd server %q, expected a URL like http://127.0.0.1:2379", s)
+		}
+		if u.Scheme == "https" {
+			tls = true
+		}
+	}
+	hasTLSFiles := lk.EtcdCAFile != "" || lk.EtcdCertFile != "" || lk.EtcdKeyFile != ""
+	if tls && lk.EtcdCAFile == "" {
+		return fmt.Errorf("https etcd servers require --etcd-cafile")
+	}
+	if !tls && hasTLSFiles {
+		return fmt.Errorf("--etcd-cafile, --etcd-certfile and --etcd-keyfile require https etcd servers")
+	}
+	if (lk.EtcdCertFile == "") != (lk.EtcdKeyFile == "") {
+		return fmt.Errorf("--etcd-certfile and --etcd-keyfile have to be set together")
+	}
+	return nil
+}
+
+func isComponent(name string) bool {
+	for _, c := range Components {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
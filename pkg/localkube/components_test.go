@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"testing"
+)
+
+func TestValidateComponents(t *testing.T) {
+	var tests = []struct {
+		description string
+		disable     []string
+		etcdServers []string
+		etcdCA      string
+		etcdCert    string
+		etcdKey     string
+		shouldErr   bool
+	}{
+		{
+			description: "all components",
+		},
+		{
+			description: "without proxy",
+			disable:     []string{ProxyName},
+		},
+		{
+			description: "external etcd",
+			disable:     []string{EtcdName},
+			etcdServers: []string{"https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			etcdCA:      "/etc/etcd/ca.crt",
+			etcdCert:    "/etc/etcd/client.crt",
+			etcdKey:     "/etc/etcd/client.key",
+		},
+		{
+			description: "external etcd over http",
+			disable:     []string{EtcdName},
+			etcdServers: []string{"http://10.0.0.2:2379"},
+		},
+		{
+			description: "https etcd without a CA",
+			disable:     []string{EtcdName},
+			etcdServers: []string{"https://10.0.0.2:2379"},
+			shouldErr:   true,
+		},
+		{
+			description: "etcd client cert without a key",
+			disable:     []string{EtcdName},
+			etcdServers: []string{"https://10.0.0.2:2379"},
+			etcdCA:      "/etc/etcd/ca.crt",
+			etcdCert:    "/etc/etcd/client.crt",
+			shouldErr:   true,
+		},
+		{
+			description: "etcd TLS files with http servers",
+			disable:     []string{EtcdName},
+			etcdServers: []string{"http://10.0.0.2:2379"},
+			etcdCA:      "/etc/etcd/ca.crt",
+			shouldErr:   true,
+		},
+		{
+			description: "unknown component",
+			disable:     []string{"dns"},
+			shouldErr:   true,
+		},
+		{
+			description: "storage provisioner",
+			disable:     []string{"storage-provisioner"},
+			shouldErr:   true,
+		},
+		{
+			description: "without apiserver",
+			disable:     []string{APIServerName},
+			shouldErr:   true,
+		},
+		{
+			description: "without etcd servers",
+			disable:     []string{EtcdName},
+			shouldErr:   true,
+		},
+		{
+			description: "etcd servers with embedded etcd",
+			etcdServers: []string{"http://10.0.0.2:2379"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid etcd server",
+			disable:     []string{EtcdName},
+			etcdServers: []string{"10.0.0.2:2379"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			lk := LocalkubeServer{
+				DisableComponents: test.disable,
+				EtcdServers:       test.etcdServers,
+				EtcdCAFile:        test.etcdCA,
+				EtcdCertFile:      test.etcdCert,
+				EtcdKeyFile:       test.etcdKey,
+			}
+			err := lk.ValidateComponents()
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestGetEtcdServers(t *testing.T) {
+	lk := LocalkubeServer{}
+	if servers := lk.GetEtcdServers(); len(servers) != 1 || servers[0] != "http://localhost:2379" {
+		t.Errorf("Expected the embedded etcd, got %v", servers)
+	}
+	lk.EtcdServers = []string{"https://10.0.0.2:2379"}
+	if servers := lk.GetEtcdServers(); len(servers) != 1 || servers[0] != "https://10.0.0.2:2379" {
+		t.Errorf("Expected the external etcd, got %v", servers)
+	}
+}
@@ -23,10 +23,10 @@ import (
 )
 
 func (lk LocalkubeServer) NewControllerManagerServer() Server {
-	return NewSimpleServer("controller-manager", serverInterval, StartControllerManagerServer(lk), noop)
+	return NewSimpleServer(ControllerManagerName, serverInterval, StartControllerManagerServer(lk), noop)
 }
 
-func StartControllerManagerServer(lk LocalkubeServer) func() error {
+func StartControllerManagerServer(lk LocalkubeServer) func(stop <-chan struct{}) error {
 	config := options.NewCMServer()
 
 	config.Kubeconfig = util.DefaultKubeConfigPath
@@ -41,10 +41,12 @@ func StartControllerManagerServer(lk LocalkubeServer) func() error {
 	config.VolumeConfiguration.EnableDynamicProvisioning = true
 	config.ServiceAccountKeyFile = lk.GetPrivateKeyCertPath()
 	config.RootCAFile = lk.GetCAPublicKeyCertPath()
+	// There is only one controller manager, and it only stops without leader election
+	config.LeaderElection.LeaderElect = false
 
 	lk.SetExtraConfigForComponent("controller-manager", &config)
 
-	return func() error {
-		return controllerManager.Run(config)
+	return func(stop <-chan struct{}) error {
+		return controllerManager.RunUntil(config, stop)
 	}
 }
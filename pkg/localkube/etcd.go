@@ -17,10 +17,14 @@ limitations under the License.
 package localkube
 
 import (
+	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/coreos/etcd/embed"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -32,6 +36,15 @@ const (
 type EtcdServer struct {
 	Etcd   *embed.Etcd
 	Config *embed.Config
+	// SnapshotPath is where a copy of the data is written when etcd is stopped
+	SnapshotPath string
+
+	// mu guards Etcd, which is set once etcd is started
+	mu sync.Mutex
+
+	// stopOnce makes later calls to Stop return the result of the first one
+	stopOnce sync.Once
+	stopErr  error
 }
 
 // NewEtcd creates a new default etcd Server using 'dataDir' for persistence. Panics if could not be configured.
@@ -41,35 +54,104 @@ func (lk LocalkubeServer) NewEtcd(dataDir string) (*EtcdServer, error) {
 
 	lk.SetExtraConfigForComponent(EtcdName, &cfg)
 	return &EtcdServer{
-		Config: cfg,
+		Config:       cfg,
+		SnapshotPath: lk.GetEtcdSnapshotPath(),
 	}, nil
 }
 
 // Start starts the etcd server and listening for client connections
 func (e *EtcdServer) Start() {
-	var err error
-	e.Etcd, err = embed.StartEtcd(e.Config)
+	etcd, err := embed.StartEtcd(e.Config)
 	if err != nil {
 		glog.Fatalf("Error starting up etcd: %s", err)
 	}
+	e.mu.Lock()
+	e.Etcd = etcd
+	e.mu.Unlock()
 
 	select {
-	case <-e.Etcd.Server.ReadyNotify():
+	case <-etcd.Server.ReadyNotify():
 		glog.Infoln("Etcd server is ready")
 	case <-time.After(60 * time.Second):
-		e.Etcd.Server.Stop() // trigger a shutdown
+		etcd.Server.Stop() // trigger a shutdown
 		glog.Fatalf("Etcd took too long to start")
 	}
 }
 
-// Stop closes all connections and stops the Etcd server
-func (e *EtcdServer) Stop() {
-	if e.Etcd != nil {
-		e.Etcd.Server.Stop()
+func (e *EtcdServer) getEtcd() *embed.Etcd {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.Etcd
+}
+
+// Stop writes a snapshot of the data, then closes all connections and stops
+// the Etcd server.  It is stopped even if the snapshot fails.
+func (e *EtcdServer) Stop(timeout time.Duration) error {
+	e.stopOnce.Do(func() { e.stopErr = e.stop(timeout) })
+	return e.stopErr
+}
+
+func (e *EtcdServer) stop(timeout time.Duration) error {
+	etcd := e.getEtcd()
+	if etcd == nil {
+		return nil
+	}
+	snapshotErr := e.snapshot(etcd)
+
+	go etcd.Close()
+	select {
+	case <-etcd.Server.StopNotify():
+	case <-time.After(timeout):
+		return fmt.Errorf("etcd didn't stop within %s", timeout)
+	}
+	return snapshotErr
+}
+
+// snapshot writes the data of etcd to the SnapshotPath, replacing the previous snapshot
+func (e *EtcdServer) snapshot(etcd *embed.Etcd) error {
+	if e.SnapshotPath == "" {
+		return nil
+	}
+	glog.Infof("Writing etcd snapshot to %s", e.SnapshotPath)
+	backend := etcd.Server.Backend()
+	backend.ForceCommit()
+	snap := backend.Snapshot()
+	defer snap.Close()
+
+	tmp := e.SnapshotPath + ".part"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "creating etcd snapshot")
+	}
+	if _, err := snap.WriteTo(f); err != nil {
+		f.Close()
+		return errors.Wrap(err, "writing etcd snapshot")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrap(err, "syncing etcd snapshot")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "closing etcd snapshot")
+	}
+	return os.Rename(tmp, e.SnapshotPath)
+}
+
+// Ready returns whether etcd was started and is serving requests
+func (e *EtcdServer) Ready() (bool, error) {
+	etcd := e.getEtcd()
+	if etcd == nil {
+		return false, nil
+	}
+	select {
+	case <-etcd.Server.ReadyNotify():
+		return true, nil
+	default:
+		return false, nil
 	}
 }
 
 // Name returns the servers unique name
-func (e EtcdServer) Name() string {
-	return e.Config.Name
+func (e *EtcdServer) Name() string {
+	return EtcdName
 }
@@ -0,0 +1,134 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// ServerStatus is the state of a server as reported by the health endpoint
+type ServerStatus struct {
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+	Restarts  int    `json:"restarts"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// HealthStatus is the response of the health endpoint
+type HealthStatus struct {
+	Ready   bool           `json:"ready"`
+	Servers []ServerStatus `json:"servers"`
+}
+
+// restartCounter is implemented by servers that are restarted when they exit
+type restartCounter interface {
+	RunStatus() (int, error)
+}
+
+// Status returns the readiness of every server, and how often they were restarted
+func (servers Servers) Status() HealthStatus {
+	status := HealthStatus{Ready: true}
+	for _, server := range servers {
+		s := ServerStatus{Name: server.Name()}
+		ready, err := server.Ready()
+		s.Ready = ready && err == nil
+		if err != nil {
+			s.LastError = err.Error()
+		}
+		if r, ok := server.(restartCounter); ok {
+			restarts, lastErr := r.RunStatus()
+			s.Restarts = restarts
+			if lastErr != nil {
+				s.LastError = lastErr.Error()
+			}
+		}
+		status.Ready = status.Ready && s.Ready
+		status.Servers = append(status.Servers, s)
+	}
+	return status
+}
+
+// healthHandler serves the status of the servers as JSON, with a 503 if any isn't ready
+func healthHandler(servers Servers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := servers.Status()
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			glog.Errorf("Error writing health status: %s", err)
+		}
+	}
+}
+
+// getHealthTLSConfig serves with the apiserver cert, and only accepts clients
+// with a cert signed by the cluster CA.
+func (lk LocalkubeServer) getHealthTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(lk.GetPublicKeyCertPath(), lk.GetPrivateKeyCertPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "loading server cert")
+	}
+	caCert, err := ioutil.ReadFile(lk.GetCAPublicKeyCertPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CA cert")
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("unable to parse CA cert %s", lk.GetCAPublicKeyCertPath())
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caCertPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}, nil
+}
+
+// StartHealthServer serves the status of the servers at /status on localhost.
+// It does nothing if the HealthPort is 0.
+func (lk LocalkubeServer) StartHealthServer(servers Servers) error {
+	if lk.HealthPort == 0 {
+		return nil
+	}
+	tlsConfig, err := lk.getHealthTLSConfig()
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/status", healthHandler(servers))
+	server := &http.Server{
+		Addr:      net.JoinHostPort("127.0.0.1", strconv.Itoa(lk.HealthPort)),
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+	go func() {
+		if err := server.ListenAndServeTLS("", ""); err != nil {
+			glog.Errorf("Error serving health status: %s", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestServersStatus(t *testing.T) {
+	apiserver := NewSimpleServer("apiserver", 0, func(<-chan struct{}) error { return nil }, noop)
+	scheduler := NewSimpleServer("scheduler", 0, func(<-chan struct{}) error { return fmt.Errorf("bind: address already in use") }, func() bool { return false })
+	for i := 0; i < 3; i++ {
+		scheduler.run()
+	}
+	apiserver.run()
+
+	var tests = []struct {
+		description string
+		servers     Servers
+		statusCode  int
+		expected    HealthStatus
+	}{
+		{
+			description: "ready",
+			servers:     Servers{apiserver},
+			statusCode:  http.StatusOK,
+			expected: HealthStatus{
+				Ready:   true,
+				Servers: []ServerStatus{{Name: "apiserver", Ready: true}},
+			},
+		},
+		{
+			description: "restarting server",
+			servers:     Servers{apiserver, scheduler},
+			statusCode:  http.StatusServiceUnavailable,
+			expected: HealthStatus{
+				Servers: []ServerStatus{
+					{Name: "apiserver", Ready: true},
+					{Name: "scheduler", Restarts: 2, LastError: "bind: address already in use"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			healthHandler(test.servers)(w, httptest.NewRequest("GET", "/status", nil))
+			if w.Code != test.statusCode {
+				t.Errorf("Expected status code %d, got %d", test.statusCode, w.Code)
+			}
+			var actual HealthStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("Error decoding status: %s", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+}
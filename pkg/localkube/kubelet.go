@@ -24,17 +24,17 @@ import (
 )
 
 func (lk LocalkubeServer) NewKubeletServer() Server {
-	return NewSimpleServer("kubelet", serverInterval, StartKubeletServer(lk), noop)
+	return NewSimpleServer(KubeletName, serverInterval, StartKubeletServer(lk), noop)
 }
 
-func StartKubeletServer(lk LocalkubeServer) func() error {
+func StartKubeletServer(lk LocalkubeServer) func(stop <-chan struct{}) error {
 	config, err := options.NewKubeletServer()
 	if err != nil {
-		return func() error { return err }
+		return func(<-chan struct{}) error { return err }
 	}
 	dnsIP, err := util.GetDNSIP(lk.ServiceClusterIPRange.String())
 	if err != nil {
-		return func() error { return err }
+		return func(<-chan struct{}) error { return err }
 	}
 
 	// Master details
@@ -78,7 +78,7 @@ func StartKubeletServer(lk LocalkubeServer) func() error {
 		config.ResolverConfig = "/etc/resolv.conf"
 	}
 
-	return func() error {
-		return kubelet.Run(config, nil)
+	return func(stop <-chan struct{}) error {
+		return kubelet.RunUntil(config, nil, stop)
 	}
 }
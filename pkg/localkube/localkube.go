@@ -55,6 +55,7 @@ type LocalkubeServer struct {
 	APIServerInsecureAddress net.IP
 	APIServerInsecurePort    int
 	APIServerName            string
+	HealthPort               int
 	ShouldGenerateCerts      bool
 	ShouldGenerateKubeconfig bool
 	ShowVersion              bool
@@ -67,6 +68,14 @@ type LocalkubeServer struct {
 	NetworkPlugin            string
 	FeatureGates             string
 	ExtraConfig              util.ExtraOptionSlice
+	// DisableComponents are the Components localkube doesn't run
+	DisableComponents []string
+	// EtcdServers replace the embedded etcd, which has to be disabled
+	EtcdServers []string
+	// The files the apiserver uses to talk to https EtcdServers
+	EtcdCAFile   string
+	EtcdCertFile string
+	EtcdKeyFile  string
 }
 
 func (lk *LocalkubeServer) AddServer(server Server) {
@@ -77,6 +86,11 @@ func (lk LocalkubeServer) GetEtcdDataDirectory() string {
 	return path.Join(lk.LocalkubeDirectory, "etcd")
 }
 
+// GetEtcdSnapshotPath is where a copy of the etcd data is written on shutdown
+func (lk LocalkubeServer) GetEtcdSnapshotPath() string {
+	return path.Join(lk.LocalkubeDirectory, "etcd-snapshot.db")
+}
+
 func (lk LocalkubeServer) GetDNSDataDirectory() string {
 	return path.Join(lk.LocalkubeDirectory, "dns")
 }
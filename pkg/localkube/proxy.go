@@ -33,10 +33,10 @@ var (
 )
 
 func (lk LocalkubeServer) NewProxyServer() Server {
-	return NewSimpleServer("proxy", serverInterval, StartProxyServer(lk), noop)
+	return NewSimpleServer(ProxyName, serverInterval, StartProxyServer(lk), noop)
 }
 
-func StartProxyServer(lk LocalkubeServer) func() error {
+func StartProxyServer(lk LocalkubeServer) func(stop <-chan struct{}) error {
 	bindaddress := lk.APIServerAddress.String()
 	if lk.APIServerInsecurePort != 0 {
 		bindaddress = lk.APIServerInsecureAddress.String()
@@ -70,7 +70,7 @@ func StartProxyServer(lk LocalkubeServer) func() error {
 
 	lk.SetExtraConfigForComponent("proxy", &config)
 
-	return func() error {
-		return opts.Run()
+	return func(stop <-chan struct{}) error {
+		return opts.RunUntil(stop)
 	}
 }
@@ -23,10 +23,10 @@ import (
 )
 
 func (lk LocalkubeServer) NewSchedulerServer() Server {
-	return NewSimpleServer("scheduler", serverInterval, StartSchedulerServer(lk), noop)
+	return NewSimpleServer(SchedulerName, serverInterval, StartSchedulerServer(lk), noop)
 }
 
-func StartSchedulerServer(lk LocalkubeServer) func() error {
+func StartSchedulerServer(lk LocalkubeServer) func(stop <-chan struct{}) error {
 	config := &componentconfig.KubeSchedulerConfiguration{}
 	opts, err := scheduler.NewOptions()
 	if err != nil {
@@ -42,14 +42,28 @@ func StartSchedulerServer(lk LocalkubeServer) func() error {
 
 	// defaults from command
 	config.EnableProfiling = true
+	// There is only one scheduler, and it only stops without leader election
+	config.LeaderElection.LeaderElect = false
 
 	lk.SetExtraConfigForComponent("scheduler", &config)
 
-	return func() error {
+	return func(stop <-chan struct{}) error {
 		s, err := scheduler.NewSchedulerServer(config, "")
 		if err != nil {
 			return err
 		}
-		return s.Run(nil)
+		// Run wants a channel it can receive from and close
+		stopCh := make(chan struct{})
+		go func() {
+			<-stop
+			close(stopCh)
+		}()
+		err = s.Run(stopCh)
+		select {
+		case <-stop:
+			return nil
+		default:
+			return err
+		}
 	}
 }
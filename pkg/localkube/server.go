@@ -17,7 +17,9 @@ limitations under the License.
 package localkube
 
 import (
+	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"k8s.io/minikube/pkg/util"
@@ -29,8 +31,8 @@ type Server interface {
 	// Start immediately starts the component.
 	Start()
 
-	// Stop begins the process of stopping the component.
-	Stop()
+	// Stop stops the component, and waits up to the timeout for it to exit.
+	Stop(timeout time.Duration) error
 
 	// Name returns a unique identifier for the component.
 	Name() string
@@ -43,12 +45,23 @@ type SimpleServer struct {
 	ComponentName string
 	Interval      time.Duration
 
-	serverRoutine func() error
+	// serverRoutine has to return once the stop channel is closed
+	serverRoutine func(stop <-chan struct{}) error
 	stopChannel   chan struct{}
+	stopOnce      sync.Once
 	readyFunc     func() bool
+	// exited is closed once serverRoutine won't be restarted anymore
+	exited chan struct{}
+
+	// runs and lastErr record the exits of serverRoutine, which util.Until restarts
+	mu      sync.Mutex
+	started bool
+	runs    int
+	lastErr error
 }
 
-func NewSimpleServer(componentName string, msInterval int32, serverRoutine func() error, ready HealthCheck) *SimpleServer {
+// NewSimpleServer creates a server whose routine runs until the stop channel is closed
+func NewSimpleServer(componentName string, msInterval int32, serverRoutine func(stop <-chan struct{}) error, ready HealthCheck) *SimpleServer {
 	return &SimpleServer{
 		ComponentName: componentName,
 		Interval:      time.Duration(msInterval) * time.Millisecond,
@@ -56,24 +69,69 @@ func NewSimpleServer(componentName string, msInterval int32, serverRoutine func(
 		serverRoutine: serverRoutine,
 		stopChannel:   make(chan struct{}),
 		readyFunc:     ready,
+		exited:        make(chan struct{}),
 	}
 }
 
 // Start calls startup function.
 func (s *SimpleServer) Start() {
-	go util.Until(s.serverRoutine, os.Stdout, s.ComponentName, s.Interval, s.stopChannel)
+	s.mu.Lock()
+	s.started = true
+	s.mu.Unlock()
+	go func() {
+		util.Until(s.run, os.Stdout, s.ComponentName, s.Interval, s.stopChannel)
+		close(s.exited)
+	}()
 }
 
-// Stop calls shutdown function.
-func (s *SimpleServer) Stop() {
-	close(s.stopChannel)
+func (s *SimpleServer) run() error {
+	s.mu.Lock()
+	s.runs++
+	s.mu.Unlock()
+
+	err := s.serverRoutine(s.stopChannel)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastErr = err
+	}
+	return err
+}
+
+// RunStatus returns how often the server was restarted and the last error it exited with
+func (s *SimpleServer) RunStatus() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	restarts := s.runs - 1
+	if restarts < 0 {
+		restarts = 0
+	}
+	return restarts, s.lastErr
+}
+
+// Stop stops the routine of the server and waits for it to return.  It can be
+// called more than once.
+func (s *SimpleServer) Stop(timeout time.Duration) error {
+	s.stopOnce.Do(func() { close(s.stopChannel) })
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if !started {
+		return nil
+	}
+	select {
+	case <-s.exited:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("%s didn't stop within %s", s.ComponentName, timeout)
+	}
 }
 
 // Name returns the name of the service.
-func (s SimpleServer) Name() string {
+func (s *SimpleServer) Name() string {
 	return s.ComponentName
 }
 
-func (s SimpleServer) Ready() (bool, error) {
+func (s *SimpleServer) Ready() (bool, error) {
 	return s.readyFunc(), nil
 }
@@ -18,6 +18,7 @@ package localkube
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -49,13 +50,60 @@ func (servers Servers) StartAll() {
 	}
 }
 
-// StopAll stops all services, starting with the last item.
-func (servers Servers) StopAll() {
-	for i := len(servers) - 1; i >= 0; i-- {
-		server := servers[i]
-		fmt.Printf("Stopping %s...\n", server.Name())
-		server.Stop()
+// StopAll stops all services in the shutdownOrder, so that nothing uses a
+// component once it is stopped.  The servers of a group are stopped at the
+// same time, each with its timeout from ShutdownTimeouts.  Servers that aren't
+// in the shutdownOrder are stopped first.  It returns a *ShutdownError if any
+// server didn't stop cleanly.
+func (servers Servers) StopAll() error {
+	ordered := map[string]bool{}
+	for _, group := range shutdownOrder {
+		for _, name := range group {
+			ordered[name] = true
+		}
+	}
+	var others Servers
+	for _, server := range servers {
+		if !ordered[server.Name()] {
+			others = append(others, server)
+		}
+	}
+
+	shutdownErr := &ShutdownError{Errors: map[string]error{}}
+	others.stopGroup(shutdownErr)
+	for _, group := range shutdownOrder {
+		var g Servers
+		for _, name := range group {
+			if server, err := servers.Get(name); err == nil {
+				g = append(g, server)
+			}
+		}
+		g.stopGroup(shutdownErr)
 	}
+	if len(shutdownErr.Errors) > 0 {
+		return shutdownErr
+	}
+	return nil
+}
+
+// stopGroup stops the servers at the same time, and records those that fail in shutdownErr
+func (servers Servers) stopGroup(shutdownErr *ShutdownError) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, server := range servers {
+		wg.Add(1)
+		go func(server Server) {
+			defer wg.Done()
+			fmt.Printf("Stopping %s...\n", server.Name())
+			if err := server.Stop(GetShutdownTimeout(server.Name())); err != nil {
+				fmt.Printf("Error stopping %s: %s\n", server.Name(), err)
+				mu.Lock()
+				shutdownErr.Errors[server.Name()] = err
+				mu.Unlock()
+			}
+		}(server)
+	}
+	wg.Wait()
 }
 
 // Start is a helper method to start the Server specified, returns error if server doesn't exist.
@@ -76,6 +124,5 @@ func (servers Servers) Stop(serverName string) error {
 		return err
 	}
 
-	server.Stop()
-	return nil
+	return server.Stop(GetShutdownTimeout(serverName))
 }
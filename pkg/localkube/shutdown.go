@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// shutdownOrder are the groups of servers StopAll stops one after the other.
+// The node components go first so they don't see the control plane go away,
+// and etcd goes last once the apiserver doesn't write to it anymore.
+var shutdownOrder = [][]string{
+	{KubeletName, ProxyName},
+	{ControllerManagerName, SchedulerName},
+	{APIServerName},
+	{EtcdName},
+}
+
+// ShutdownTimeouts are how long each server gets to stop
+var ShutdownTimeouts = map[string]time.Duration{
+	KubeletName:           10 * time.Second,
+	ProxyName:             10 * time.Second,
+	ControllerManagerName: 10 * time.Second,
+	SchedulerName:         10 * time.Second,
+	APIServerName:         30 * time.Second,
+	EtcdName:              30 * time.Second,
+}
+
+// defaultShutdownTimeout is the timeout of servers without one in ShutdownTimeouts
+const defaultShutdownTimeout = 10 * time.Second
+
+// GetShutdownTimeout returns how long the server gets to stop
+func GetShutdownTimeout(name string) time.Duration {
+	if t, ok := ShutdownTimeouts[name]; ok {
+		return t
+	}
+	return defaultShutdownTimeout
+}
+
+// ShutdownFailedExitCode is the exit code of localkube when servers don't stop
+// cleanly, or'ed with the shutdownExitCodes of those servers.
+const ShutdownFailedExitCode = 64
+
+// shutdownExitCodes are the bits of the exit code for each server that failed to stop
+var shutdownExitCodes = map[string]int{
+	KubeletName:           1 << 0,
+	ProxyName:             1 << 1,
+	ControllerManagerName: 1 << 2,
+	SchedulerName:         1 << 3,
+	APIServerName:         1 << 4,
+	EtcdName:              1 << 5,
+}
+
+// ShutdownError holds the errors of the servers that didn't stop cleanly
+type ShutdownError struct {
+	Errors map[string]error
+}
+
+func (e *ShutdownError) Error() string {
+	var names []string
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var msgs []string
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, e.Errors[name]))
+	}
+	return "servers didn't stop cleanly: " + strings.Join(msgs, ", ")
+}
+
+// ExitCode returns the exit code that tells which servers failed to stop
+func (e *ShutdownError) ExitCode() int {
+	code := ShutdownFailedExitCode
+	for name := range e.Errors {
+		code |= shutdownExitCodes[name]
+	}
+	return code
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer records when it is stopped
+type fakeServer struct {
+	name    string
+	stopErr error
+	stopped *[]string
+	mu      *sync.Mutex
+}
+
+func (f fakeServer) Start()               {}
+func (f fakeServer) Name() string         { return f.name }
+func (f fakeServer) Ready() (bool, error) { return true, nil }
+func (f fakeServer) Stop(timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	*f.stopped = append(*f.stopped, f.name)
+	return f.stopErr
+}
+
+func TestStopAll(t *testing.T) {
+	var stopped []string
+	var mu sync.Mutex
+	server := func(name string, err error) Server {
+		return fakeServer{name: name, stopErr: err, stopped: &stopped, mu: &mu}
+	}
+	servers := Servers{
+		server(EtcdName, fmt.Errorf("snapshot failed")),
+		server(APIServerName, nil),
+		server(ControllerManagerName, nil),
+		server(KubeletName, nil),
+		server("other", nil),
+	}
+
+	err := servers.StopAll()
+	expected := []string{"other", KubeletName, ControllerManagerName, APIServerName, EtcdName}
+	if !reflect.DeepEqual(stopped, expected) {
+		t.Errorf("Expected the servers to stop in order %v, got %v", expected, stopped)
+	}
+	shutdownErr, ok := err.(*ShutdownError)
+	if !ok {
+		t.Fatalf("Expected a ShutdownError, got %v", err)
+	}
+	if code := shutdownErr.ExitCode(); code != ShutdownFailedExitCode|shutdownExitCodes[EtcdName] {
+		t.Errorf("Unexpected exit code %d", code)
+	}
+}
+
+func TestStopSimpleServer(t *testing.T) {
+	stoppable := NewSimpleServer("stoppable", 0, func(stop <-chan struct{}) error {
+		<-stop
+		return nil
+	}, noop)
+	stoppable.Start()
+	if err := stoppable.Stop(time.Second); err != nil {
+		t.Errorf("Unexpected error stopping server: %s", err)
+	}
+	// Stopping it again, like StopAll after a component was stopped, is fine
+	if err := stoppable.Stop(time.Second); err != nil {
+		t.Errorf("Unexpected error stopping server twice: %s", err)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	running := make(chan struct{})
+	stuck := NewSimpleServer("stuck", 0, func(stop <-chan struct{}) error {
+		close(running)
+		<-block
+		return nil
+	}, noop)
+	stuck.Start()
+	<-running
+	if err := stuck.Stop(10 * time.Millisecond); err == nil {
+		t.Errorf("Expected a timeout stopping a stuck server")
+	}
+
+	// A server that was never started has nothing to wait for
+	idle := NewSimpleServer("idle", 0, func(stop <-chan struct{}) error {
+		<-stop
+		return nil
+	}, noop)
+	if err := idle.Stop(10 * time.Millisecond); err != nil {
+		t.Errorf("Unexpected error stopping server: %s", err)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// pvDeleteTimeout is how long to wait for a PV to be deleted when it has to
+// be recreated to change its hostPath.
+var pvDeleteTimeout = 30 * time.Second
+
+// errBoundImmutable is returned by setHostPath for bound PVs, which can't be
+// recreated, when the apiserver doesn't allow their hostPath to change.
+var errBoundImmutable = errors.New("the apiserver doesn't allow the hostPath of a bound PV to change")
+
+// MigratePVs moves the data of PVs created by this provisioner outside of pvDir
+// into pvDir, and rewrites their hostPath to match. Only PVs that aren't used
+// by a pod are migrated. PVs in use, and PVs whose data can't be found, are
+// left untouched.
+func MigratePVs(client corev1.PersistentVolumeInterface, pods corev1.PodsGetter, pvDir string) error {
+	pvs, err := client.List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing PVs")
+	}
+
+	var errs []error
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if _, ok := pv.Annotations[identityAnnotation]; !ok || pv.Spec.HostPath == nil {
+			continue
+		}
+		src := path.Clean(pv.Spec.HostPath.Path)
+		if path.Dir(src) == path.Clean(pvDir) {
+			continue
+		}
+		if _, err := os.Stat(src); err != nil {
+			glog.Warningf("Not migrating PV %s: %v", pv.Name, err)
+			continue
+		}
+		pod, err := podUsingPV(pods, pv)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "checking pods using PV %s", pv.Name))
+			continue
+		}
+		if pod != "" {
+			glog.Warningf("Not migrating PV %s: it is used by pod %s", pv.Name, pod)
+			continue
+		}
+		err = migratePV(client, pv, path.Join(pvDir, pv.Name))
+		if errors.Cause(err) == errBoundImmutable {
+			glog.Warningf("Not migrating PV %s: %v", pv.Name, err)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "migrating PV %s", pv.Name))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// podUsingPV returns the namespace/name of a running pod that uses the claim
+// bound to pv, or "" if there is none.
+func podUsingPV(pods corev1.PodsGetter, pv *v1.PersistentVolume) (string, error) {
+	claim := pv.Spec.ClaimRef
+	if pv.Status.Phase != v1.VolumeBound || claim == nil {
+		return "", nil
+	}
+	list, err := pods.Pods(claim.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range list.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim.Name {
+				return pod.Namespace + "/" + pod.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func migratePV(client corev1.PersistentVolumeInterface, pv *v1.PersistentVolume, dst string) error {
+	src := pv.Spec.HostPath.Path
+	glog.Infof("Migrating PV %s from %s to %s", pv.Name, src, dst)
+	if err := moveDir(src, dst); err != nil {
+		return errors.Wrap(err, "moving PV data")
+	}
+
+	if err := setHostPath(client, pv, dst); err != nil {
+		if err := moveDir(dst, src); err != nil {
+			glog.Errorf("Error moving PV %s back to %s: %v", pv.Name, src, err)
+		}
+		if err == errBoundImmutable {
+			return err
+		}
+		return errors.Wrap(err, "updating PV")
+	}
+	return nil
+}
+
+// setHostPath rewrites the hostPath of pv. Newer apiservers don't allow the
+// volume source of a PV to change, so a PV that isn't bound is recreated when
+// the update is rejected. The claimRef is kept so that a released PV stays
+// released.
+func setHostPath(client corev1.PersistentVolumeInterface, pv *v1.PersistentVolume, hostPath string) error {
+	updated := pv.DeepCopy()
+	updated.Spec.HostPath.Path = hostPath
+	_, err := client.Update(updated)
+	if err == nil || !apierrors.IsInvalid(err) {
+		return err
+	}
+	if pv.Status.Phase == v1.VolumeBound {
+		return errBoundImmutable
+	}
+
+	glog.Infof("Recreating PV %s to change its hostPath", pv.Name)
+	if err := client.Delete(pv.Name, &metav1.DeleteOptions{}); err != nil {
+		return errors.Wrap(err, "deleting")
+	}
+	if err := wait.PollImmediate(time.Second, pvDeleteTimeout, func() (bool, error) {
+		_, err := client.Get(pv.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}); err != nil {
+		return errors.Wrap(err, "waiting for deletion")
+	}
+
+	updated.ObjectMeta = metav1.ObjectMeta{
+		Name:        pv.Name,
+		Labels:      pv.Labels,
+		Annotations: pv.Annotations,
+	}
+	updated.Status = v1.PersistentVolumeStatus{}
+	if _, err := client.Create(updated); err != nil {
+		return errors.Wrap(err, "creating")
+	}
+	return nil
+}
+
+// moveDir moves src to dst, copying the tree when src and dst are on
+// different mounts.
+func moveDir(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return errors.Errorf("%s already exists", dst)
+	}
+	if err := os.MkdirAll(path.Dir(dst), 0777); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyDir(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return errors.Wrap(err, "copying")
+	}
+	return os.RemoveAll(src)
+}
+
+// copyDir copies the tree at src to dst, preserving modes and ownership
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			if err := os.Mkdir(target, info.Mode().Perm()); err != nil {
+				return err
+			}
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if err := copyFile(p, target, info.Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("can't copy %s: unsupported file mode %s", p, info.Mode())
+		}
+
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			if err := os.Lchown(target, int(st.Uid), int(st.Gid)); err != nil {
+				return err
+			}
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			// Set the mode explicitly so that it doesn't depend on the umask
+			return os.Chmod(target, info.Mode())
+		}
+		return nil
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+)
+
+type mockPVs struct {
+	fake.FakePersistentVolumes
+	pvs map[string]*v1.PersistentVolume
+	// immutable rejects hostPath changes like newer apiservers do
+	immutable bool
+	created   []string
+}
+
+var pvResource = schema.GroupResource{Resource: "persistentvolumes"}
+
+func (m *mockPVs) List(metav1.ListOptions) (*v1.PersistentVolumeList, error) {
+	l := &v1.PersistentVolumeList{}
+	for _, pv := range m.pvs {
+		l.Items = append(l.Items, *pv)
+	}
+	return l, nil
+}
+
+func (m *mockPVs) Get(name string, _ metav1.GetOptions) (*v1.PersistentVolume, error) {
+	pv, ok := m.pvs[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(pvResource, name)
+	}
+	return pv, nil
+}
+
+func (m *mockPVs) Update(pv *v1.PersistentVolume) (*v1.PersistentVolume, error) {
+	old, ok := m.pvs[pv.Name]
+	if !ok {
+		return nil, apierrors.NewNotFound(pvResource, pv.Name)
+	}
+	if m.immutable && old.Spec.HostPath.Path != pv.Spec.HostPath.Path {
+		return nil, apierrors.NewInvalid(schema.GroupKind{Kind: "PersistentVolume"}, pv.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "persistentvolumesource"), "is immutable after creation"),
+		})
+	}
+	m.pvs[pv.Name] = pv
+	return pv, nil
+}
+
+func (m *mockPVs) Delete(name string, _ *metav1.DeleteOptions) error {
+	// The pv-protection finalizer keeps bound PVs around
+	if pv := m.pvs[name]; pv.Status.Phase == v1.VolumeBound && len(pv.Finalizers) > 0 {
+		return nil
+	}
+	delete(m.pvs, name)
+	return nil
+}
+
+func (m *mockPVs) Create(pv *v1.PersistentVolume) (*v1.PersistentVolume, error) {
+	m.pvs[pv.Name] = pv
+	m.created = append(m.created, pv.Name)
+	return pv, nil
+}
+
+type mockPods struct {
+	fake.FakePods
+	pods []v1.Pod
+}
+
+func (m *mockPods) Pods(string) corev1.PodInterface {
+	return m
+}
+
+func (m *mockPods) List(metav1.ListOptions) (*v1.PodList, error) {
+	return &v1.PodList{Items: m.pods}, nil
+}
+
+func newPod(name, claim string, phase v1.PodPhase) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: v1.PodSpec{
+			Volumes: []v1.Volume{{
+				Name: "data",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+				},
+			}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func newHostPathPV(name, hostPath string, provisioned bool, phase v1.PersistentVolumePhase) *v1.PersistentVolume {
+	pv := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			ResourceVersion: "1",
+			Finalizers:      []string{"kubernetes.io/pv-protection"},
+		},
+		Spec: v1.PersistentVolumeSpec{
+			ClaimRef: &v1.ObjectReference{Namespace: "default", Name: name + "-claim"},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				HostPath: &v1.HostPathVolumeSource{Path: hostPath},
+			},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: phase},
+	}
+	if provisioned {
+		pv.Annotations = map[string]string{identityAnnotation: "id"}
+	}
+	return pv
+}
+
+func TestMigratePVs(t *testing.T) {
+	for _, immutable := range []bool{false, true} {
+		tempDir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatalf("Error making temp directory: %s", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		oldDir := filepath.Join(tempDir, "old")
+		pvDir := filepath.Join(tempDir, "new")
+		for _, name := range []string{"migrate", "unused", "used", "other"} {
+			if err := os.MkdirAll(filepath.Join(oldDir, name), 0777); err != nil {
+				t.Fatalf("Error making PV directory: %s", err)
+			}
+		}
+		if err := ioutil.WriteFile(filepath.Join(oldDir, "migrate", "data"), []byte("data"), 0644); err != nil {
+			t.Fatalf("Error writing PV data: %s", err)
+		}
+
+		pvs := &mockPVs{
+			immutable: immutable,
+			pvs: map[string]*v1.PersistentVolume{
+				"migrate": newHostPathPV("migrate", filepath.Join(oldDir, "migrate"), true, v1.VolumeReleased),
+				// Bound, but no running pod uses the claim
+				"unused": newHostPathPV("unused", filepath.Join(oldDir, "unused"), true, v1.VolumeBound),
+				"used":   newHostPathPV("used", filepath.Join(oldDir, "used"), true, v1.VolumeBound),
+				// Not created by the provisioner
+				"other":   newHostPathPV("other", filepath.Join(oldDir, "other"), false, v1.VolumeAvailable),
+				"current": newHostPathPV("current", filepath.Join(pvDir, "current"), true, v1.VolumeBound),
+				// Data was lost, e.g. a tmpfs
+				"missing": newHostPathPV("missing", filepath.Join(oldDir, "missing"), true, v1.VolumeAvailable),
+			},
+		}
+		pods := &mockPods{pods: []v1.Pod{
+			newPod("running", "used-claim", v1.PodRunning),
+			newPod("done", "unused-claim", v1.PodSucceeded),
+		}}
+
+		if err := MigratePVs(pvs, pods, pvDir); err != nil {
+			t.Fatalf("Error migrating PVs: %s", err)
+		}
+
+		pv := pvs.pvs["migrate"]
+		if pv.Spec.HostPath.Path != filepath.Join(pvDir, "migrate") {
+			t.Errorf("Expected PV to be moved to %s, got %s", pvDir, pv.Spec.HostPath.Path)
+		}
+		if pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Name != "migrate-claim" {
+			t.Errorf("Expected PV to keep its claimRef, got %v", pv.Spec.ClaimRef)
+		}
+		if pv.Annotations[identityAnnotation] != "id" {
+			t.Errorf("Expected PV to keep its annotations, got %v", pv.Annotations)
+		}
+		if immutable && (len(pvs.created) != 1 || pvs.created[0] != "migrate") {
+			t.Errorf("Expected only the released PV to be recreated, created %v", pvs.created)
+		}
+		if !immutable && len(pvs.created) != 0 {
+			t.Errorf("Expected PV to be updated, created %v", pvs.created)
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(pvDir, "migrate", "data"))
+		if err != nil || string(data) != "data" {
+			t.Errorf("Expected PV data to be moved, got %q: %v", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(oldDir, "migrate")); !os.IsNotExist(err) {
+			t.Errorf("Expected old PV directory to be removed: %v", err)
+		}
+
+		// A bound PV can only be migrated if its hostPath can be updated
+		unused := filepath.Join(oldDir, "unused")
+		if !immutable {
+			unused = filepath.Join(pvDir, "unused")
+		}
+		if len(pvs.pvs["unused"].Finalizers) == 0 {
+			t.Errorf("Expected the bound PV to keep its finalizers")
+		}
+		if _, err := os.Stat(unused); err != nil {
+			t.Errorf("Expected the data of the bound PV in %s: %v", unused, err)
+		}
+
+		for name, expected := range map[string]string{
+			"unused":  unused,
+			"used":    filepath.Join(oldDir, "used"),
+			"other":   filepath.Join(oldDir, "other"),
+			"current": filepath.Join(pvDir, "current"),
+			"missing": filepath.Join(oldDir, "missing"),
+		} {
+			if path := pvs.pvs[name].Spec.HostPath.Path; path != expected {
+				t.Errorf("Expected PV %s to stay at %s, got %s", name, expected, path)
+			}
+		}
+	}
+}
+
+func TestCopyDir(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	src := filepath.Join(tempDir, "src")
+	dst := filepath.Join(tempDir, "dst")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0700); err != nil {
+		t.Fatalf("Error making directory: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "sub", "file"), []byte("contents"), 0600); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+	if err := os.Symlink("sub/file", filepath.Join(src, "link")); err != nil {
+		t.Fatalf("Error making symlink: %s", err)
+	}
+
+	if err := copyDir(src, dst); err != nil {
+		t.Fatalf("Error copying directory: %s", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dst, "sub"))
+	if err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("Expected directory with mode 0700, got %v: %v", info, err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dst, "link"))
+	if err != nil || string(data) != "contents" {
+		t.Errorf("Expected contents through symlink, got %q: %v", data, err)
+	}
+	info, err = os.Stat(filepath.Join(dst, "sub", "file"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected file with mode 0600, got %v: %v", info, err)
+	}
+
+	// Special files like sockets can't be copied
+	l, err := net.Listen("unix", filepath.Join(src, "socket"))
+	if err != nil {
+		t.Fatalf("Error making socket: %s", err)
+	}
+	defer l.Close()
+	if err := copyDir(src, filepath.Join(tempDir, "dst2")); err == nil {
+		t.Errorf("Expected an error copying a socket")
+	}
+}
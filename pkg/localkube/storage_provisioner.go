@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -33,7 +36,18 @@ import (
 	restclient "k8s.io/client-go/rest"
 )
 
-const provisionerName = "k8s.io/minikube-hostpath"
+const (
+	provisionerName = "k8s.io/minikube-hostpath"
+
+	// DefaultPVDir is where PV-backing directories are created by default.
+	// It is on the persistent disk of the minikube ISO.
+	DefaultPVDir = "/var/lib/localkube/hostpath-provisioner"
+
+	// PVDirEnv is the environment variable that overrides DefaultPVDir
+	PVDirEnv = "STORAGE_PROVISIONER_PV_DIR"
+
+	identityAnnotation = "hostPathProvisionerIdentity"
+)
 
 type hostPathProvisioner struct {
 	// The directory to create PV-backing directories in
@@ -42,12 +56,18 @@ type hostPathProvisioner struct {
 	// Identity of this hostPathProvisioner, generated. Used to identify "this"
 	// provisioner's PVs.
 	identity types.UID
+
+	quotas projectQuotas
+	// Serializes the allocation of project IDs
+	quotaMu sync.Mutex
 }
 
-func NewHostPathProvisioner() controller.Provisioner {
+// NewHostPathProvisioner returns a provisioner that creates PVs in pvDir
+func NewHostPathProvisioner(pvDir string) controller.Provisioner {
 	return &hostPathProvisioner{
-		pvDir:    "/tmp/hostpath-provisioner",
+		pvDir:    pvDir,
 		identity: uuid.NewUUID(),
+		quotas:   linuxProjectQuotas{},
 	}
 }
 
@@ -55,6 +75,15 @@ var _ controller.Provisioner = &hostPathProvisioner{}
 
 // Provision creates a storage asset and returns a PV object representing it.
 func (p *hostPathProvisioner) Provision(options controller.VolumeOptions) (*v1.PersistentVolume, error) {
+	enforcement, err := capacityEnforcement(options.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	capacity := options.PVC.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)]
+	if enforcement == CapacityEnforcementQuota && capacity.Value() <= 0 {
+		return nil, errors.New("capacity can't be enforced without a storage request")
+	}
+
 	path := path.Join(p.pvDir, options.PVName)
 
 	if err := os.MkdirAll(path, 0777); err != nil {
@@ -70,14 +99,14 @@ func (p *hostPathProvisioner) Provision(options controller.VolumeOptions) (*v1.P
 		ObjectMeta: metav1.ObjectMeta{
 			Name: options.PVName,
 			Annotations: map[string]string{
-				"hostPathProvisionerIdentity": string(p.identity),
+				identityAnnotation: string(p.identity),
 			},
 		},
 		Spec: v1.PersistentVolumeSpec{
 			PersistentVolumeReclaimPolicy: options.PersistentVolumeReclaimPolicy,
 			AccessModes:                   options.PVC.Spec.AccessModes,
 			Capacity: v1.ResourceList{
-				v1.ResourceName(v1.ResourceStorage): options.PVC.Spec.Resources.Requests[v1.ResourceName(v1.ResourceStorage)],
+				v1.ResourceName(v1.ResourceStorage): capacity,
 			},
 			PersistentVolumeSource: v1.PersistentVolumeSource{
 				HostPath: &v1.HostPathVolumeSource{
@@ -87,13 +116,47 @@ func (p *hostPathProvisioner) Provision(options controller.VolumeOptions) (*v1.P
 		},
 	}
 
+	if enforcement == CapacityEnforcementQuota {
+		id, err := p.setQuota(path, capacity.Value())
+		if err != nil {
+			os.RemoveAll(path)
+			return nil, errors.Wrap(err, "enforcing capacity")
+		}
+		pv.Annotations[projectIDAnnotation] = strconv.FormatUint(uint64(id), 10)
+	}
+
 	return pv, nil
 }
 
+// setQuota gives dir a new project ID and limits the space it can use to bytes
+func (p *hostPathProvisioner) setQuota(dir string, bytes int64) (uint32, error) {
+	p.quotaMu.Lock()
+	defer p.quotaMu.Unlock()
+
+	enforced, err := p.quotas.Enforced(dir)
+	if err != nil {
+		return 0, errors.Wrap(err, "checking project quotas")
+	}
+	if !enforced {
+		return 0, errors.Errorf("the filesystem of %s doesn't enforce project quotas, it has to be mounted with prjquota", dir)
+	}
+	id, err := nextProjectID(p.quotas, p.pvDir)
+	if err != nil {
+		return 0, errors.Wrap(err, "allocating project ID")
+	}
+	if err := p.quotas.SetProjectID(dir, id); err != nil {
+		return 0, errors.Wrap(err, "setting project ID")
+	}
+	if err := p.quotas.SetLimit(dir, id, bytes); err != nil {
+		return 0, errors.Wrap(err, "setting quota")
+	}
+	return id, nil
+}
+
 // Delete removes the storage asset that was created by Provision represented
 // by the given PV.
 func (p *hostPathProvisioner) Delete(volume *v1.PersistentVolume) error {
-	ann, ok := volume.Annotations["hostPathProvisionerIdentity"]
+	ann, ok := volume.Annotations[identityAnnotation]
 	if !ok {
 		return errors.New("identity annotation not found on PV")
 	}
@@ -102,6 +165,15 @@ func (p *hostPathProvisioner) Delete(volume *v1.PersistentVolume) error {
 	}
 
 	path := path.Join(p.pvDir, volume.Name)
+	if ann, ok := volume.Annotations[projectIDAnnotation]; ok {
+		id, err := strconv.ParseUint(ann, 10, 32)
+		if err == nil {
+			err = p.quotas.SetLimit(path, uint32(id), 0)
+		}
+		if err != nil {
+			glog.Warningf("Error removing the quota of PV %s: %v", volume.Name, err)
+		}
+	}
 	if err := os.RemoveAll(path); err != nil {
 		return errors.Wrap(err, "removing hostpath PV")
 	}
@@ -109,8 +181,11 @@ func (p *hostPathProvisioner) Delete(volume *v1.PersistentVolume) error {
 	return nil
 }
 
-// Start storage provisioner server
-func StartStorageProvisioner() error {
+// StartStorageProvisioner starts the storage provisioner server, creating PVs
+// in pvDir. PVs provisioned in another directory are first migrated to pvDir.
+// The usage annotation of the PVs is updated every usageInterval, unless it
+// is 0.
+func StartStorageProvisioner(pvDir string, usageInterval time.Duration) error {
 	config, err := restclient.InClusterConfig()
 	if err != nil {
 		return err
@@ -127,14 +202,26 @@ func StartStorageProvisioner() error {
 		return fmt.Errorf("Error getting server version: %v", err)
 	}
 
+	if err := MigratePVs(clientset.CoreV1().PersistentVolumes(), clientset.CoreV1(), pvDir); err != nil {
+		glog.Errorf("Error migrating PVs to %s: %v", pvDir, err)
+	}
+
 	// Create the provisioner: it implements the Provisioner interface expected by
 	// the controller
-	hostPathProvisioner := NewHostPathProvisioner()
+	hostPathProvisioner := NewHostPathProvisioner(pvDir)
 
 	// Start the provision controller which will dynamically provision hostPath
 	// PVs
 	pc := controller.NewProvisionController(clientset, provisionerName, hostPathProvisioner, serverVersion.GitVersion)
 
+	if usageInterval > 0 {
+		go wait.Forever(func() {
+			if err := UpdateUsage(clientset.CoreV1().PersistentVolumes(), pvDir); err != nil {
+				glog.Warningf("Error updating PV usage: %v", err)
+			}
+		}, usageInterval)
+	}
+
 	glog.Info("Starting storage provisioner server")
 	pc.Run(wait.NeverStop)
 	return nil
@@ -0,0 +1,264 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CapacityEnforcementParameter is the StorageClass parameter that selects
+	// how the requested capacity of a PV is enforced.
+	CapacityEnforcementParameter = "capacityEnforcement"
+
+	// CapacityEnforcementNone doesn't limit the size of PVs
+	CapacityEnforcementNone = "none"
+
+	// CapacityEnforcementQuota limits the size of PVs with XFS or ext4 project
+	// quotas. The filesystem of the PV directory has to have project quotas
+	// enabled, which the minikube ISO does for its data disk.
+	CapacityEnforcementQuota = "quota"
+
+	projectIDAnnotation = "hostPathProvisionerProjectID"
+
+	// firstProjectID is the lowest project ID given to a PV, lower IDs are
+	// left for the admin.
+	firstProjectID = 1000
+)
+
+// capacityEnforcement returns the capacity enforcement selected by the
+// StorageClass parameters.
+func capacityEnforcement(parameters map[string]string) (string, error) {
+	for k, v := range parameters {
+		if k != CapacityEnforcementParameter {
+			return "", errors.Errorf("unknown StorageClass parameter %q", k)
+		}
+		if v != CapacityEnforcementNone && v != CapacityEnforcementQuota {
+			return "", errors.Errorf("invalid %s %q, must be %q or %q", k, v, CapacityEnforcementNone, CapacityEnforcementQuota)
+		}
+	}
+	if v, ok := parameters[CapacityEnforcementParameter]; ok {
+		return v, nil
+	}
+	return CapacityEnforcementNone, nil
+}
+
+// projectQuotas manages the project quotas of PV directories
+type projectQuotas interface {
+	// Enforced returns whether the filesystem of dir enforces project quota limits
+	Enforced(dir string) (bool, error)
+	// ProjectID returns the project ID of dir
+	ProjectID(dir string) (uint32, error)
+	// SetProjectID sets the project ID of dir, which is inherited by new files
+	SetProjectID(dir string, id uint32) error
+	// SetLimit limits the space used by a project on the filesystem of dir.
+	// A limit of 0 removes the limit.
+	SetLimit(dir string, id uint32, bytes int64) error
+}
+
+// nextProjectID returns a project ID that isn't used by the directories in pvDir
+func nextProjectID(quotas projectQuotas, pvDir string) (uint32, error) {
+	files, err := ioutil.ReadDir(pvDir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	next := uint32(firstProjectID)
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		id, err := quotas.ProjectID(filepath.Join(pvDir, f.Name()))
+		if err != nil {
+			return 0, errors.Wrapf(err, "getting project ID of %s", f.Name())
+		}
+		if id >= next {
+			next = id + 1
+		}
+	}
+	return next, nil
+}
+
+// linuxProjectQuotas implements projectQuotas with the generic quotactl
+// interface, which is supported by both XFS and ext4.
+type linuxProjectQuotas struct{}
+
+// From linux/fs.h and linux/quota.h
+const (
+	fsIocFsGetXattr    = 0x801c581f
+	fsIocFsSetXattr    = 0x401c5820
+	fsXflagProjInherit = 0x200
+
+	qSetQuota    = 0x800008
+	prjQuota     = 2
+	qifBLimits   = 1
+	qifDqblkSize = 1024
+)
+
+type fsxattr struct {
+	Xflags     uint32
+	Extsize    uint32
+	Nextents   uint32
+	Projid     uint32
+	Cowextsize uint32
+	Pad        [8]byte
+}
+
+type ifDqblk struct {
+	BHardlimit uint64
+	BSoftlimit uint64
+	CurSpace   uint64
+	IHardlimit uint64
+	ISoftlimit uint64
+	CurInodes  uint64
+	BTime      uint64
+	ITime      uint64
+	Valid      uint32
+}
+
+func fsxattrIoctl(dir string, req uintptr, attr *fsxattr) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(attr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (linuxProjectQuotas) ProjectID(dir string) (uint32, error) {
+	var attr fsxattr
+	if err := fsxattrIoctl(dir, fsIocFsGetXattr, &attr); err != nil {
+		return 0, err
+	}
+	return attr.Projid, nil
+}
+
+func (linuxProjectQuotas) SetProjectID(dir string, id uint32) error {
+	var attr fsxattr
+	if err := fsxattrIoctl(dir, fsIocFsGetXattr, &attr); err != nil {
+		return err
+	}
+	attr.Projid = id
+	attr.Xflags |= fsXflagProjInherit
+	return fsxattrIoctl(dir, fsIocFsSetXattr, &attr)
+}
+
+// Enforced checks the mount options of dir, since without prjquota the
+// kernel only accounts the usage of projects and ignores their limits.
+func (linuxProjectQuotas) Enforced(dir string) (bool, error) {
+	m, err := findMount(dir)
+	if err != nil {
+		return false, errors.Wrap(err, "finding mount")
+	}
+	for _, o := range m.options {
+		if o == "prjquota" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (linuxProjectQuotas) SetLimit(dir string, id uint32, bytes int64) error {
+	m, err := findMount(dir)
+	if err != nil {
+		return errors.Wrap(err, "finding mount")
+	}
+	device := m.device
+
+	blocks := uint64((bytes + qifDqblkSize - 1) / qifDqblkSize)
+	dqblk := ifDqblk{
+		BHardlimit: blocks,
+		BSoftlimit: blocks,
+		Valid:      qifBLimits,
+	}
+	devicePtr, err := syscall.BytePtrFromString(device)
+	if err != nil {
+		return err
+	}
+	cmd := qSetQuota<<8 | prjQuota
+	if _, _, errno := syscall.Syscall6(syscall.SYS_QUOTACTL, uintptr(cmd), uintptr(unsafe.Pointer(devicePtr)), uintptr(id), uintptr(unsafe.Pointer(&dqblk)), 0, 0); errno != 0 {
+		return errors.Wrapf(errno, "setting quota on %s, does it have project quotas enabled?", device)
+	}
+	return nil
+}
+
+// mount is an entry of /proc/self/mountinfo
+type mount struct {
+	device string
+	// options are the options of the filesystem, not of the mount point
+	options []string
+}
+
+// findMount returns the mount containing dir
+func findMount(dir string) (mount, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return mount{}, err
+	}
+	defer f.Close()
+	return parseMountinfo(f, dir)
+}
+
+// parseMountinfo returns the mount containing dir, read from the mountinfo
+// format of /proc/self/mountinfo.
+func parseMountinfo(mountinfo io.Reader, dir string) (mount, error) {
+	dir = filepath.Clean(dir)
+	var mountPoint string
+	var m mount
+	scanner := bufio.NewScanner(mountinfo)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if len(fields) < 5 || sep < 0 || len(fields) < sep+4 {
+			continue
+		}
+		mp := fields[4]
+		if mp != "/" && dir != mp && !strings.HasPrefix(dir, mp+"/") {
+			continue
+		}
+		// Later mounts of the same mount point hide the earlier ones
+		if len(mp) >= len(mountPoint) {
+			mountPoint = mp
+			m = mount{device: fields[sep+2], options: strings.Split(fields[sep+3], ",")}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return mount{}, err
+	}
+	if m.device == "" {
+		return mount{}, errors.Errorf("no mount found for %s", dir)
+	}
+	return m, nil
+}
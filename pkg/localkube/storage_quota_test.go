@@ -0,0 +1,231 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/r2d4/external-storage/lib/controller"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type fakeProjectQuotas struct {
+	ids        map[string]uint32
+	limits     map[uint32]int64
+	unenforced bool
+}
+
+func newFakeProjectQuotas() *fakeProjectQuotas {
+	return &fakeProjectQuotas{
+		ids:    map[string]uint32{},
+		limits: map[uint32]int64{},
+	}
+}
+
+func (f *fakeProjectQuotas) Enforced(dir string) (bool, error) {
+	return !f.unenforced, nil
+}
+
+func (f *fakeProjectQuotas) ProjectID(dir string) (uint32, error) {
+	return f.ids[dir], nil
+}
+
+func (f *fakeProjectQuotas) SetProjectID(dir string, id uint32) error {
+	f.ids[dir] = id
+	return nil
+}
+
+func (f *fakeProjectQuotas) SetLimit(dir string, id uint32, bytes int64) error {
+	if bytes == 0 {
+		delete(f.limits, id)
+		return nil
+	}
+	f.limits[id] = bytes
+	return nil
+}
+
+func TestCapacityEnforcement(t *testing.T) {
+	var tests = []struct {
+		parameters  map[string]string
+		expected    string
+		shouldError bool
+	}{
+		{
+			expected: CapacityEnforcementNone,
+		},
+		{
+			parameters: map[string]string{CapacityEnforcementParameter: "none"},
+			expected:   CapacityEnforcementNone,
+		},
+		{
+			parameters: map[string]string{CapacityEnforcementParameter: "quota"},
+			expected:   CapacityEnforcementQuota,
+		},
+		{
+			parameters:  map[string]string{CapacityEnforcementParameter: "loop"},
+			shouldError: true,
+		},
+		{
+			parameters:  map[string]string{"type": "pd-ssd"},
+			shouldError: true,
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := capacityEnforcement(test.parameters)
+		if err != nil && !test.shouldError {
+			t.Errorf("Unexpected error for %v: %s", test.parameters, err)
+		}
+		if err == nil && test.shouldError {
+			t.Errorf("Expected error for %v, got %s", test.parameters, actual)
+		}
+		if actual != test.expected {
+			t.Errorf("Expected %q for %v, got %q", test.expected, test.parameters, actual)
+		}
+	}
+}
+
+func TestProvisionQuota(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	quotas := newFakeProjectQuotas()
+	p := &hostPathProvisioner{
+		pvDir:    tempDir,
+		identity: "id",
+		quotas:   quotas,
+	}
+	// A directory that isn't a PV already uses the first ID
+	if err := os.Mkdir(filepath.Join(tempDir, "other"), 0777); err != nil {
+		t.Fatalf("Error making directory: %s", err)
+	}
+	quotas.ids[filepath.Join(tempDir, "other")] = firstProjectID
+
+	provision := func(name, enforcement, size string) (*v1.PersistentVolume, error) {
+		pvc := &v1.PersistentVolumeClaim{}
+		if size != "" {
+			pvc.Spec.Resources.Requests = v1.ResourceList{
+				v1.ResourceStorage: resource.MustParse(size),
+			}
+		}
+		return p.Provision(controller.VolumeOptions{
+			PVName:     name,
+			PVC:        pvc,
+			Parameters: map[string]string{CapacityEnforcementParameter: enforcement},
+		})
+	}
+
+	pv1, err := provision("pv1", CapacityEnforcementQuota, "1Gi")
+	if err != nil {
+		t.Fatalf("Error provisioning: %s", err)
+	}
+	pv2, err := provision("pv2", CapacityEnforcementQuota, "1Mi")
+	if err != nil {
+		t.Fatalf("Error provisioning: %s", err)
+	}
+	pv3, err := provision("pv3", CapacityEnforcementNone, "1Mi")
+	if err != nil {
+		t.Fatalf("Error provisioning: %s", err)
+	}
+	if _, err := provision("pv4", CapacityEnforcementQuota, ""); err == nil {
+		t.Errorf("Expected error enforcing the capacity of a PV without a storage request")
+	}
+
+	for _, test := range []struct {
+		pv    *v1.PersistentVolume
+		id    string
+		limit int64
+	}{
+		{pv1, "1001", 1 << 30},
+		{pv2, "1002", 1 << 20},
+	} {
+		if id := test.pv.Annotations[projectIDAnnotation]; id != test.id {
+			t.Errorf("Expected project ID %s for %s, got %s", test.id, test.pv.Name, id)
+		}
+		id := quotas.ids[test.pv.Spec.HostPath.Path]
+		if limit := quotas.limits[id]; limit != test.limit {
+			t.Errorf("Expected limit %d for %s, got %d", test.limit, test.pv.Name, limit)
+		}
+	}
+	if _, ok := pv3.Annotations[projectIDAnnotation]; ok {
+		t.Errorf("Expected no project ID without capacity enforcement, got %v", pv3.Annotations)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "pv4")); !os.IsNotExist(err) {
+		t.Errorf("Expected the directory of a failed PV to not exist: %v", err)
+	}
+
+	if err := p.Delete(pv1); err != nil {
+		t.Fatalf("Error deleting PV: %s", err)
+	}
+	if _, ok := quotas.limits[1001]; ok {
+		t.Errorf("Expected the quota of a deleted PV to be removed, got %v", quotas.limits)
+	}
+
+	quotas.unenforced = true
+	if _, err := provision("pv5", CapacityEnforcementQuota, "1Mi"); err == nil {
+		t.Errorf("Expected error enforcing the capacity of a PV on a filesystem without prjquota")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "pv5")); !os.IsNotExist(err) {
+		t.Errorf("Expected the directory of a failed PV to not exist: %v", err)
+	}
+	if _, err := provision("pv6", CapacityEnforcementNone, "1Mi"); err != nil {
+		t.Errorf("Error provisioning without capacity enforcement: %s", err)
+	}
+}
+
+func TestParseMountinfo(t *testing.T) {
+	mountinfo := `15 20 0:14 / /sys rw,nosuid,nodev,noexec,relatime shared:7 - sysfs sysfs rw
+1 0 0:1 / / rw - rootfs rootfs rw
+34 1 8:1 / /mnt/sda1 rw,relatime shared:2 - ext4 /dev/sda1 rw,prjquota
+35 1 8:1 /var/lib/localkube /var/lib/localkube rw,relatime shared:2 - ext4 /dev/sda1 rw,prjquota
+36 1 8:17 / /var/lib/localkube/hostpath-provisioner rw shared:3 - xfs /dev/sdb1 rw,prjquota
+37 1 8:33 / /var/lib/localkube/hostpath rw shared:4 - xfs /dev/sdc1 rw
+`
+	var tests = []struct {
+		dir     string
+		device  string
+		options string
+	}{
+		{"/var/lib/localkube/hostpath-provisioner/pvc-1", "/dev/sdb1", "rw,prjquota"},
+		{"/var/lib/localkube/hostpath-provisioner", "/dev/sdb1", "rw,prjquota"},
+		{"/var/lib/localkube/other", "/dev/sda1", "rw,prjquota"},
+		{"/var/lib/localkube/hostpath-other", "/dev/sda1", "rw,prjquota"},
+		{"/var/lib/localkube/hostpath/pvc-1", "/dev/sdc1", "rw"},
+		{"/tmp/hostpath-provisioner", "rootfs", "rw"},
+	}
+
+	for _, test := range tests {
+		actual, err := parseMountinfo(strings.NewReader(mountinfo), test.dir)
+		if err != nil {
+			t.Errorf("Error finding mount of %s: %s", test.dir, err)
+		}
+		if actual.device != test.device {
+			t.Errorf("Expected device %s for %s, got %s", test.device, test.dir, actual.device)
+		}
+		if options := strings.Join(actual.options, ","); options != test.options {
+			t.Errorf("Expected options %s for %s, got %s", test.options, test.dir, options)
+		}
+	}
+}
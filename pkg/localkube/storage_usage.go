@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// UsageAnnotation is the PV annotation that holds the disk space used by the
+// PV, as a quantity.
+const UsageAnnotation = "hostPathProvisionerUsage"
+
+// UpdateUsage sets the usage annotation of the PVs created by the provisioner
+// in pvDir.
+func UpdateUsage(client corev1.PersistentVolumeInterface, pvDir string) error {
+	pvs, err := client.List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing PVs")
+	}
+
+	var errs []error
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if _, ok := pv.Annotations[identityAnnotation]; !ok || pv.Spec.HostPath == nil {
+			continue
+		}
+		if filepath.Dir(filepath.Clean(pv.Spec.HostPath.Path)) != filepath.Clean(pvDir) {
+			continue
+		}
+
+		used, err := dirUsage(pv.Spec.HostPath.Path)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "getting usage of PV %s", pv.Name))
+			continue
+		}
+		usage := resource.NewQuantity(used, resource.BinarySI).String()
+		if pv.Annotations[UsageAnnotation] == usage {
+			continue
+		}
+
+		updated := pv.DeepCopy()
+		updated.Annotations[UsageAnnotation] = usage
+		if _, err := client.Update(updated); err != nil {
+			errs = append(errs, errors.Wrapf(err, "updating PV %s", pv.Name))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// dirUsage returns the disk space used by the tree at dir, in bytes. Hard
+// linked files are only counted once.
+func dirUsage(dir string) (int64, error) {
+	type inode struct {
+		dev uint64
+		ino uint64
+	}
+	seen := map[inode]bool{}
+	var used int64
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			used += info.Size()
+			return nil
+		}
+		if st.Nlink > 1 {
+			key := inode{uint64(st.Dev), uint64(st.Ino)}
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
+		}
+		// Blocks are always 512 bytes, whatever the block size of the filesystem
+		used += int64(st.Blocks) * 512
+		return nil
+	})
+	return used, err
+}
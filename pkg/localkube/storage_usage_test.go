@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestUpdateUsage(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, name := range []string{"used", "empty"} {
+		if err := os.Mkdir(filepath.Join(tempDir, name), 0777); err != nil {
+			t.Fatalf("Error making PV directory: %s", err)
+		}
+	}
+	data := make([]byte, 1<<20)
+	for i := range data {
+		data[i] = 1
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, "used", "data"), data, 0644); err != nil {
+		t.Fatalf("Error writing PV data: %s", err)
+	}
+	// Hard links don't use more space
+	if err := os.Link(filepath.Join(tempDir, "used", "data"), filepath.Join(tempDir, "used", "link")); err != nil {
+		t.Fatalf("Error linking PV data: %s", err)
+	}
+
+	pvs := &mockPVs{
+		pvs: map[string]*v1.PersistentVolume{
+			"used":  newHostPathPV("used", filepath.Join(tempDir, "used"), true, v1.VolumeBound),
+			"empty": newHostPathPV("empty", filepath.Join(tempDir, "empty"), true, v1.VolumeBound),
+			"other": newHostPathPV("other", filepath.Join(tempDir, "used"), false, v1.VolumeBound),
+		},
+	}
+
+	if err := UpdateUsage(pvs, tempDir); err != nil {
+		t.Fatalf("Error updating usage: %s", err)
+	}
+
+	usage, err := resource.ParseQuantity(pvs.pvs["used"].Annotations[UsageAnnotation])
+	if err != nil {
+		t.Fatalf("Error parsing usage: %s", err)
+	}
+	// Leave room for the blocks of the directory and filesystem overhead
+	if usage.Value() < 1<<20 || usage.Value() > 2<<20 {
+		t.Errorf("Expected usage of about 1Mi, got %s", usage.String())
+	}
+
+	usage, err = resource.ParseQuantity(pvs.pvs["empty"].Annotations[UsageAnnotation])
+	if err != nil {
+		t.Fatalf("Error parsing usage: %s", err)
+	}
+	if usage.Value() >= 1<<20 {
+		t.Errorf("Expected usage of an empty PV to be small, got %s", usage.String())
+	}
+
+	if _, ok := pvs.pvs["other"].Annotations[UsageAnnotation]; ok {
+		t.Errorf("Expected no usage on a PV not created by the provisioner, got %v", pvs.pvs["other"].Annotations)
+	}
+}
@@ -55,6 +55,42 @@ func (a *Addon) IsEnabled() (bool, error) {
 	return a.enabled, nil
 }
 
+// AddonTemplateData holds the values the templated addon manifests are rendered with
+type AddonTemplateData struct {
+	// DNSIP is the cluster IP of the DNS service
+	DNSIP string
+}
+
+// NewAddonTemplateData returns the template data of a cluster with the given
+// service CIDR, or the default one if it's empty
+func NewAddonTemplateData(serviceCIDR string) (AddonTemplateData, error) {
+	if serviceCIDR == "" {
+		serviceCIDR = util.DefaultServiceCIDR
+	}
+	dnsIP, err := util.GetDNSIP(serviceCIDR)
+	if err != nil {
+		return AddonTemplateData{}, errors.Wrap(err, "getting dns ip")
+	}
+	return AddonTemplateData{DNSIP: dnsIP.String()}, nil
+}
+
+// Files returns the files of the addon, with its templates rendered with data
+func (a *Addon) Files(data AddonTemplateData) ([]CopyableFile, error) {
+	var files []CopyableFile
+	for _, asset := range a.Assets {
+		if !asset.IsTemplate() {
+			files = append(files, asset)
+			continue
+		}
+		f, err := asset.Evaluate(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rendering %s", asset.AssetName)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
 var Addons = map[string]*Addon{
 	"addon-manager": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
@@ -100,7 +136,7 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"coreDNS-configmap.yaml",
 			"0640"),
-		NewBinDataAsset(
+		NewBinDataTemplateAsset(
 			"deploy/addons/coredns/coreDNS-svc.yaml",
 			constants.AddonsPath,
 			"coreDNS-svc.yaml",
@@ -132,7 +168,7 @@ var Addons = map[string]*Addon{
 			constants.AddonsPath,
 			"kube-dns-cm.yaml",
 			"0640"),
-		NewBinDataAsset(
+		NewBinDataTemplateAsset(
 			"deploy/addons/kube-dns/kube-dns-svc.yaml",
 			constants.AddonsPath,
 			"kube-dns-svc.yaml",
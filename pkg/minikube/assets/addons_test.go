@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestAddonFiles(t *testing.T) {
+	var tests = []struct {
+		serviceCIDR string
+		clusterIP   string
+	}{
+		{"", "clusterIP: 10.96.0.10"},
+		{"172.20.0.0/16", "clusterIP: 172.20.0.10"},
+		{"fd00:10:96::/108", "clusterIP: fd00:10:96::a"},
+	}
+
+	for _, test := range tests {
+		data, err := NewAddonTemplateData(test.serviceCIDR)
+		if err != nil {
+			t.Fatalf("Error getting template data for %q: %s", test.serviceCIDR, err)
+		}
+		for _, name := range []string{"kube-dns", "coredns"} {
+			files, err := Addons[name].Files(data)
+			if err != nil {
+				t.Fatalf("Error rendering %s: %s", name, err)
+			}
+			var found bool
+			for _, f := range files {
+				b, err := ioutil.ReadAll(f)
+				if err != nil {
+					t.Fatalf("Error reading %s: %s", f.GetTargetName(), err)
+				}
+				if strings.Contains(string(b), "{{") {
+					t.Errorf("Expected %s to be rendered:\n%s", f.GetTargetName(), b)
+				}
+				if strings.Contains(string(b), test.clusterIP) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected %s to contain %q for %q", name, test.clusterIP, test.serviceCIDR)
+			}
+		}
+	}
+
+	if _, err := NewAddonTemplateData("not-a-cidr"); err == nil {
+		t.Errorf("Expected an error for an invalid service CIDR")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"text/template"
 
 	"github.com/pkg/errors"
 )
@@ -134,11 +135,12 @@ func NewMemoryAsset(d []byte, targetDir, targetName, permissions string) *Memory
 
 type BinDataAsset struct {
 	BaseAsset
+	isTemplate bool
 }
 
 func NewBinDataAsset(assetName, targetDir, targetName, permissions string) *BinDataAsset {
 	m := &BinDataAsset{
-		BaseAsset{
+		BaseAsset: BaseAsset{
 			AssetName:   assetName,
 			TargetDir:   targetDir,
 			TargetName:  targetName,
@@ -149,6 +151,32 @@ func NewBinDataAsset(assetName, targetDir, targetName, permissions string) *BinD
 	return m
 }
 
+// NewBinDataTemplateAsset returns an asset that is a text/template, which has to
+// be rendered with Evaluate before it is copied
+func NewBinDataTemplateAsset(assetName, targetDir, targetName, permissions string) *BinDataAsset {
+	m := NewBinDataAsset(assetName, targetDir, targetName, permissions)
+	m.isTemplate = true
+	return m
+}
+
+// IsTemplate returns whether the asset has to be rendered with Evaluate
+func (m *BinDataAsset) IsTemplate() bool {
+	return m.isTemplate
+}
+
+// Evaluate renders the template of the asset with data
+func (m *BinDataAsset) Evaluate(data interface{}) (*MemoryAsset, error) {
+	t, err := template.New(m.AssetName).Parse(string(m.data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", m.AssetName)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, errors.Wrapf(err, "executing %s", m.AssetName)
+	}
+	return NewMemoryAsset(b.Bytes(), m.TargetDir, m.TargetName, m.Permissions), nil
+}
+
 func (m *BinDataAsset) loadData() error {
 	contents, err := Asset(m.AssetName)
 	if err != nil {
@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// AuditPolicyMetadata selects the built-in audit policy, which logs the
+// metadata of every request.
+const AuditPolicyMetadata = "metadata"
+
+const metadataAuditPolicy = `apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+omitStages:
+  - "RequestReceived"
+rules:
+  - level: Metadata
+`
+
+// AuditLogStdout makes the apiserver write audit events to its stdout
+const AuditLogStdout = "-"
+
+// Audit log rotation settings passed to the localkube apiserver
+const (
+	AuditLogMaxAge     = "30"
+	AuditLogMaxBackups = "3"
+	AuditLogMaxSize    = "100"
+)
+
+// ValidateAuditPolicy returns an error if the value of --audit-policy is
+// neither a built-in policy nor a readable file.
+func ValidateAuditPolicy(policy string) error {
+	if policy == "" || policy == AuditPolicyMetadata {
+		return nil
+	}
+	if _, err := os.Stat(policy); err != nil {
+		return errors.Wrapf(err, "audit policy %s", policy)
+	}
+	return nil
+}
+
+// GetAuditPolicyAsset returns the audit policy to copy into the VM, or nil if
+// audit logging isn't enabled.
+func GetAuditPolicyAsset(k8s KubernetesConfig) (assets.CopyableFile, error) {
+	switch k8s.AuditPolicy {
+	case "":
+		return nil, nil
+	case AuditPolicyMetadata:
+		return assets.NewMemoryAssetTarget([]byte(metadataAuditPolicy), constants.AuditPolicyFile, "0640"), nil
+	default:
+		f, err := assets.NewFileAsset(k8s.AuditPolicy, constants.AuditPolicyDir, path.Base(constants.AuditPolicyFile), "0640")
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading audit policy %s", k8s.AuditPolicy)
+		}
+		return f, nil
+	}
+}
+
+// GetAuditAPIServerOptions returns the flags of the kubeadm apiserver that enable
+// audit logging with the policy from GetAuditPolicyAsset. kubeadm mounts extra
+// volumes read-only, so the events are written to the container log instead of
+// a file.
+func GetAuditAPIServerOptions(k8s KubernetesConfig) map[string]string {
+	if k8s.AuditPolicy == "" {
+		return nil
+	}
+	return map[string]string{
+		"audit-policy-file": constants.AuditPolicyFile,
+		"audit-log-path":    AuditLogStdout,
+	}
+}
+
+// PrepareAuditLogDir creates the directory the localkube apiserver writes its audit log to.
+func PrepareAuditLogDir(cmd CommandRunner, k8s KubernetesConfig) error {
+	if k8s.AuditPolicy == "" {
+		return nil
+	}
+	if err := cmd.Run(fmt.Sprintf("sudo mkdir -p %s", constants.AuditLogDir)); err != nil {
+		return errors.Wrap(err, "creating audit log dir")
+	}
+	return nil
+}
+
+// GetAuditLogsTo writes the tail of the localkube apiserver audit log to out.
+func GetAuditLogsTo(cmd CommandRunner, follow bool, out io.Writer) error {
+	flags := "-n 100"
+	if follow {
+		flags += " -F"
+	}
+	logsCommand := fmt.Sprintf("sudo tail %s %s", flags, constants.AuditLogFile)
+	if err := cmd.CombinedOutputTo(logsCommand, out); err != nil {
+		return errors.Wrap(err, "getting audit logs")
+	}
+	return nil
+}
+
+// AuditEventWriter passes the audit events in the container log of the kubeadm
+// apiserver to Out, and drops the other lines the apiserver logs.
+type AuditEventWriter struct {
+	Out io.Writer
+	// line is the incomplete last line written so far
+	line []byte
+}
+
+var auditEventKind = []byte(`"kind":"Event"`)
+
+func (w *AuditEventWriter) Write(p []byte) (int, error) {
+	w.line = append(w.line, p...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeEvent(w.line[:i+1]); err != nil {
+			return 0, err
+		}
+		w.line = w.line[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes the last line if it is an audit event without a trailing newline
+func (w *AuditEventWriter) Flush() error {
+	line := w.line
+	w.line = nil
+	if len(line) == 0 {
+		return nil
+	}
+	return w.writeEvent(append(line, '\n'))
+}
+
+func (w *AuditEventWriter) writeEvent(line []byte) error {
+	if !bytes.Contains(line, auditEventKind) {
+		return nil
+	}
+	_, err := w.Out.Write(line)
+	return err
+}
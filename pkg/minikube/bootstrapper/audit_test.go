@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestGetAuditPolicyAsset(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	policyFile := filepath.Join(tempDir, "my-policy.yaml")
+	policy := []byte("apiVersion: audit.k8s.io/v1beta1\nkind: Policy\nrules:\n  - level: RequestResponse\n")
+	if err := ioutil.WriteFile(policyFile, policy, 0644); err != nil {
+		t.Fatalf("Error writing policy: %s", err)
+	}
+
+	tests := []struct {
+		description string
+		policy      string
+		contains    string
+		shouldErr   bool
+	}{
+		{
+			description: "no policy",
+		},
+		{
+			description: "built-in policy",
+			policy:      AuditPolicyMetadata,
+			contains:    "level: Metadata",
+		},
+		{
+			description: "policy file",
+			policy:      policyFile,
+			contains:    "level: RequestResponse",
+		},
+		{
+			description: "missing policy file",
+			policy:      filepath.Join(tempDir, "missing.yaml"),
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if err := ValidateAuditPolicy(test.policy); (err != nil) != test.shouldErr {
+				t.Fatalf("Unexpected validation result: %v", err)
+			}
+			f, err := GetAuditPolicyAsset(KubernetesConfig{AuditPolicy: test.policy})
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if test.contains == "" {
+				if f != nil {
+					t.Fatalf("Expected no asset, got %+v", f)
+				}
+				return
+			}
+			if actual := filepath.Join(f.GetTargetDir(), f.GetTargetName()); actual != constants.AuditPolicyFile {
+				t.Errorf("Expected target %s, got %s", constants.AuditPolicyFile, actual)
+			}
+			contents, err := ioutil.ReadAll(f)
+			if err != nil {
+				t.Fatalf("Error reading asset: %s", err)
+			}
+			if !bytes.Contains(contents, []byte(test.contains)) {
+				t.Errorf("Expected %s to contain %s", contents, test.contains)
+			}
+		})
+	}
+}
+
+func TestAuditEventWriter(t *testing.T) {
+	event := `{"kind":"Event","apiVersion":"audit.k8s.io/v1beta1","level":"Metadata","verb":"get"}`
+	var out bytes.Buffer
+	w := &AuditEventWriter{Out: &out}
+	for _, chunk := range []string{
+		"I0101 00:00:00.000000       1 server.go:121] Version: v1.10.0\n" + event[:20],
+		event[20:] + "\n",
+		"E0101 00:00:01.000000       1 reflector.go:205] \"kind\" missing\n",
+		event,
+	} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Error writing: %s", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Error flushing: %s", err)
+	}
+	if expected := event + "\n" + event + "\n"; out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -29,9 +29,30 @@ type Bootstrapper interface {
 	StartCluster(KubernetesConfig) error
 	UpdateCluster(KubernetesConfig) error
 	RestartCluster(KubernetesConfig) error
+	// StopCluster shuts Kubernetes down gracefully before the machine is stopped
+	StopCluster(KubernetesConfig) error
+	// ResetCluster wipes the state of the cluster, so StartCluster creates a new one
+	ResetCluster(KubernetesConfig) error
 	GetClusterLogsTo(follow bool, out io.Writer) error
 	SetupCerts(cfg KubernetesConfig) error
+	// ReloadCerts restarts the control plane so it serves the certs copied by SetupCerts
+	ReloadCerts(cfg KubernetesConfig) error
+	// UpdateNodeIP reconfigures a running cluster for a new NodeIP, after the VM got a new IP
+	UpdateNodeIP(cfg KubernetesConfig) error
 	GetClusterStatus() (string, error)
+	// GetComponentStatus returns the state of each component, or nil if the bootstrapper can't tell
+	GetComponentStatus() ([]ComponentStatus, error)
+	// MaintainEtcd runs EtcdDefrag or EtcdCompact on the etcd of the cluster and returns its output
+	MaintainEtcd(cfg KubernetesConfig, op string) (string, error)
+}
+
+// ComponentStatus is the state of a component of the cluster
+type ComponentStatus struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+	// Restarts counts how often the component exited and was started again
+	Restarts  int    `json:"restarts"`
+	LastError string `json:"lastError,omitempty"`
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -42,12 +63,30 @@ type KubernetesConfig struct {
 	APIServerName     string
 	APIServerNames    []string
 	APIServerIPs      []net.IP
-	DNSDomain         string
-	ContainerRuntime  string
-	NetworkPlugin     string
-	FeatureGates      string
-	ServiceCIDR       string
-	ExtraOptions      util.ExtraOptionSlice
+	// APIServerPort is the port the apiserver listens on, 0 means util.APIServerPort
+	APIServerPort    int
+	DNSDomain        string
+	ContainerRuntime string
+	NetworkPlugin    string
+	CNI              string
+	PodCIDR          string
+	FeatureGates     string
+	ServiceCIDR      string
+	ExtraOptions     util.ExtraOptionSlice
+	// ForceExtraOptions skips the validation of ExtraOptions against the flags of each component
+	ForceExtraOptions bool
+	// AuditPolicy is a path to an audit policy file on the host, or a built-in policy
+	AuditPolicy string
+	// ControlPlaneFiles are copied into the VM and mounted into control plane components
+	ControlPlaneFiles []ControlPlaneFile
+	// SharedCA signs the certs of the profile with the CA in the minikube home,
+	// which is shared with the other profiles that use it
+	SharedCA bool
+	// NodeResourcePreset sizes the resources the kubelet reserves, "" means none
+	NodeResourcePreset string
+	// NodeMemory (in MB) and NodeCPUs are the resources of the node, 0 when unknown
+	NodeMemory int
+	NodeCPUs   int
 
 	ShouldLoadCachedImages bool
 }
@@ -57,6 +96,23 @@ const (
 	BootstrapperTypeKubeadm   = "kubeadm"
 )
 
+// GetAPIServerPort returns the port the apiserver listens on. Profiles saved
+// before the port was configurable use the default port.
+func GetAPIServerPort(k8s KubernetesConfig) int {
+	if k8s.APIServerPort == 0 {
+		return util.APIServerPort
+	}
+	return k8s.APIServerPort
+}
+
+// GetServiceCIDR returns the range service IPs are allocated from
+func GetServiceCIDR(k8s KubernetesConfig) string {
+	if k8s.ServiceCIDR == "" {
+		return util.DefaultServiceCIDR
+	}
+	return k8s.ServiceCIDR
+}
+
 func GetCachedImageList(version string, bootstrapper string) []string {
 	switch bootstrapper {
 	case BootstrapperTypeLocalkube:
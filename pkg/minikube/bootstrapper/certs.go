@@ -17,10 +17,15 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -28,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/clientcmd/api/latest"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/kubeconfig"
@@ -38,11 +44,252 @@ var (
 		"ca.crt", "ca.key", "apiserver.crt", "apiserver.key", "proxy-client-ca.crt",
 		"proxy-client-ca.key", "proxy-client.crt", "proxy-client.key",
 	}
+
+	// The certificate authorities, and the certificates they sign
+	caCerts   = []string{"ca.crt", "proxy-client-ca.crt"}
+	leafCerts = []string{"client.crt", "apiserver.crt", "proxy-client.crt"}
 )
 
+// GetCertsDir returns the directory with the certificates of a profile
+func GetCertsDir(profile string) string {
+	return constants.GetProfilePath(profile)
+}
+
+// CertPath returns the path of a certificate or key of the current profile. With
+// a shared CA, the certificate authorities are in the minikube home instead, where
+// every profile finds them.
+func CertPath(k8s KubernetesConfig, name string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".crt"), ".key") + ".crt"
+	if k8s.SharedCA {
+		for _, ca := range caCerts {
+			if ca == base {
+				return filepath.Join(constants.GetMinipath(), name)
+			}
+		}
+	}
+	return filepath.Join(GetCertsDir(config.GetMachineName()), name)
+}
+
+// MigrateLegacyCerts copies the leaf certificates every profile used to share in
+// the minikube home into the directory of the profile. The certificate
+// authorities stay in the minikube home: an existing cluster keeps the CA its
+// users already trust, and has to use it as a shared CA from now on. It returns
+// whether anything was copied.
+func MigrateLegacyCerts(profile string) (bool, error) {
+	dir := GetCertsDir(profile)
+	if util.CanReadFile(filepath.Join(dir, "apiserver.crt")) || !util.CanReadFile(constants.MakeMiniPath("apiserver.crt")) {
+		return false, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, errors.Wrapf(err, "creating %s", dir)
+	}
+	for _, name := range leafCerts {
+		for _, f := range []string{name, strings.TrimSuffix(name, ".crt") + ".key"} {
+			if err := copyLegacyCert(f, dir); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// copyLegacyCert copies a certificate or key from the minikube home into dir, if it exists
+func copyLegacyCert(name, dir string) error {
+	src := constants.MakeMiniPath(name)
+	data, err := ioutil.ReadFile(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", src)
+	}
+	perms := os.FileMode(0644)
+	if strings.HasSuffix(name, ".key") {
+		perms = 0600
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, name), data, perms); err != nil {
+		return errors.Wrapf(err, "copying %s", name)
+	}
+	return nil
+}
+
+// GenerateUserCert generates a client certificate for a user of the cluster,
+// signed by the cluster CA, and returns the paths of the certificate and key.
+func GenerateUserCert(k8s KubernetesConfig, name string, groups []string, validFor time.Duration) (string, string, error) {
+	if validFor <= 0 {
+		return "", "", errors.Errorf("the certificate has to be valid for a positive duration, got %s", validFor)
+	}
+	for _, n := range append([]string{name}, groups...) {
+		if err := validateUserName(n); err != nil {
+			return "", "", err
+		}
+	}
+	dir := filepath.Join(GetCertsDir(config.GetMachineName()), "users")
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	if err := util.GenerateClientCert(certPath, keyPath, name, groups, validFor, CertPath(k8s, "ca.crt"), CertPath(k8s, "ca.key")); err != nil {
+		return "", "", errors.Wrapf(err, "generating client certificate for %s", name)
+	}
+	return certPath, keyPath, nil
+}
+
+// validateUserName rejects user and group names that can't be used as the name
+// of a certificate file in the users directory
+func validateUserName(name string) error {
+	if name == "" || strings.Contains(name, "/") || strings.Contains(name, "..") {
+		return errors.Errorf("invalid user or group name %q, it must not be empty or contain '/' or '..'", name)
+	}
+	return nil
+}
+
+// DeleteCerts removes the certificates of a profile, including those of its
+// users. A shared CA is kept.
+func DeleteCerts(profile string) error {
+	dir := GetCertsDir(profile)
+	if err := os.RemoveAll(filepath.Join(dir, "users")); err != nil {
+		return errors.Wrap(err, "removing user certificates")
+	}
+	for _, name := range append(append([]string{}, caCerts...), leafCerts...) {
+		for _, f := range []string{name, strings.TrimSuffix(name, ".crt") + ".key"} {
+			if err := os.Remove(filepath.Join(dir, f)); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "removing %s", f)
+			}
+		}
+	}
+	return nil
+}
+
+// CertExpiryWarning is how long before a certificate expires minikube starts warning about it
+const CertExpiryWarning = 30 * 24 * time.Hour
+
+// CertInfo describes a certificate generated by minikube
+type CertInfo struct {
+	Name     string
+	Path     string
+	Subject  string
+	IsCA     bool
+	NotAfter time.Time
+	DNSNames []string
+	IPs      []net.IP
+}
+
+// SANs returns the subject alternative names of the certificate
+func (c CertInfo) SANs() []string {
+	sans := append([]string{}, c.DNSNames...)
+	for _, ip := range c.IPs {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// CheckCerts returns the certificates of the current profile. Certificates that
+// haven't been generated yet are skipped.
+func CheckCerts(k8s KubernetesConfig) ([]CertInfo, error) {
+	var infos []CertInfo
+	for _, name := range append(append([]string{}, caCerts...), leafCerts...) {
+		p := CertPath(k8s, name)
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			continue
+		}
+		cert, err := util.ReadCert(p)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, CertInfo{
+			Name:     name,
+			Path:     p,
+			Subject:  cert.Subject.CommonName,
+			IsCA:     cert.IsCA,
+			NotAfter: cert.NotAfter,
+			DNSNames: cert.DNSNames,
+			IPs:      cert.IPAddresses,
+		})
+	}
+	return infos, nil
+}
+
+// ExpiringCerts returns the certificates that expire before now+within, soonest first
+func ExpiringCerts(infos []CertInfo, now time.Time, within time.Duration) []CertInfo {
+	var expiring []CertInfo
+	for _, c := range infos {
+		if c.NotAfter.Before(now.Add(within)) {
+			expiring = append(expiring, c)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].NotAfter.Before(expiring[j].NotAfter) })
+	return expiring
+}
+
+// RotateCerts generates new keys for the leaf certificates, and with rotateCA
+// new certificate authorities, before signing them again.  Rotating a shared CA
+// invalidates the certificates of every profile that uses it.
+func RotateCerts(k8s KubernetesConfig, rotateCA bool) error {
+	names := leafCerts
+	if rotateCA {
+		names = append(append([]string{}, leafCerts...), caCerts...)
+	}
+	for _, name := range names {
+		for _, f := range []string{name, strings.TrimSuffix(name, ".crt") + ".key"} {
+			if err := os.Remove(CertPath(k8s, f)); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "removing %s", f)
+			}
+		}
+	}
+	return generateCerts(k8s)
+}
+
+// NodeIPChanged returns whether the apiserver certificate lacks the node IP,
+// which happens when the VM gets a new IP.  It is false without a certificate.
+func NodeIPChanged(k8s KubernetesConfig) (bool, error) {
+	p := CertPath(k8s, "apiserver.crt")
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		return false, nil
+	}
+	cert, err := util.ReadCert(p)
+	if err != nil {
+		return false, err
+	}
+	ip := net.ParseIP(k8s.NodeIP)
+	for _, certIP := range cert.IPAddresses {
+		if certIP.Equal(ip) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// UpdateAPIServerCert signs the apiserver certificate again for the node IP,
+// unless SetupCerts already did, and copies it into the VM.  The other
+// certificates are left alone.
+func UpdateAPIServerCert(cmd CommandRunner, k8s KubernetesConfig) error {
+	changed, err := NodeIPChanged(k8s)
+	if err != nil {
+		return errors.Wrap(err, "checking apiserver cert")
+	}
+	if changed {
+		glog.Infof("Updating the apiserver certificate for IP: %s\n", k8s.NodeIP)
+		if err := generateCerts(k8s, "apiserver.crt"); err != nil {
+			return errors.Wrap(err, "Error generating apiserver cert")
+		}
+	}
+	for _, name := range []string{"apiserver.crt", "apiserver.key"} {
+		perms := "0644"
+		if strings.HasSuffix(name, ".key") {
+			perms = "0600"
+		}
+		f, err := assets.NewFileAsset(CertPath(k8s, name), util.DefaultCertPath, name, perms)
+		if err != nil {
+			return err
+		}
+		if err := cmd.Copy(f); err != nil {
+			return errors.Wrapf(err, "copying %s", name)
+		}
+	}
+	return nil
+}
+
 // SetupCerts gets the generated credentials required to talk to the APIServer.
 func SetupCerts(cmd CommandRunner, k8s KubernetesConfig) error {
-	localPath := constants.GetMinipath()
 	glog.Infof("Setting up certificates for IP: %s\n", k8s.NodeIP)
 
 	if err := generateCerts(k8s); err != nil {
@@ -52,7 +299,7 @@ func SetupCerts(cmd CommandRunner, k8s KubernetesConfig) error {
 	copyableFiles := []assets.CopyableFile{}
 
 	for _, cert := range certs {
-		p := filepath.Join(localPath, cert)
+		p := CertPath(k8s, cert)
 		perms := "0644"
 		if strings.HasSuffix(cert, ".key") {
 			perms = "0600"
@@ -66,7 +313,7 @@ func SetupCerts(cmd CommandRunner, k8s KubernetesConfig) error {
 
 	kubeCfgSetup := &kubeconfig.KubeConfigSetup{
 		ClusterName:          k8s.NodeName,
-		ClusterServerAddress: "https://localhost:8443",
+		ClusterServerAddress: fmt.Sprintf("https://localhost:%d", GetAPIServerPort(k8s)),
 		ClientCertificate:    path.Join(util.DefaultCertPath, "apiserver.crt"),
 		ClientKey:            path.Join(util.DefaultCertPath, "apiserver.key"),
 		CertificateAuthority: path.Join(util.DefaultCertPath, "ca.crt"),
@@ -92,19 +339,19 @@ func SetupCerts(cmd CommandRunner, k8s KubernetesConfig) error {
 	return nil
 }
 
-func generateCerts(k8s KubernetesConfig) error {
-	serviceIP, err := util.GetServiceClusterIP(k8s.ServiceCIDR)
+// generateCerts generates the certificate authorities if they are missing, and
+// signs the leaf certificates, or only those named.
+func generateCerts(k8s KubernetesConfig, names ...string) error {
+	serviceIP, err := util.GetServiceClusterIP(GetServiceCIDR(k8s))
 	if err != nil {
 		return errors.Wrap(err, "getting service cluster ip")
 	}
 
-	localPath := constants.GetMinipath()
-
-	caCertPath := filepath.Join(localPath, "ca.crt")
-	caKeyPath := filepath.Join(localPath, "ca.key")
+	caCertPath := CertPath(k8s, "ca.crt")
+	caKeyPath := CertPath(k8s, "ca.key")
 
-	proxyClientCACertPath := filepath.Join(localPath, "proxy-client-ca.crt")
-	proxyClientCAKeyPath := filepath.Join(localPath, "proxy-client-ca.key")
+	proxyClientCACertPath := CertPath(k8s, "proxy-client-ca.crt")
+	proxyClientCAKeyPath := CertPath(k8s, "proxy-client-ca.key")
 
 	caCertSpecs := []struct {
 		certPath string
@@ -132,6 +379,7 @@ func generateCerts(k8s KubernetesConfig) error {
 		util.GetAlternateDNS(k8s.DNSDomain)...)
 
 	signedCertSpecs := []struct {
+		name           string
 		certPath       string
 		keyPath        string
 		subject        string
@@ -141,8 +389,9 @@ func generateCerts(k8s KubernetesConfig) error {
 		caKeyPath      string
 	}{
 		{ // Client cert
-			certPath:       filepath.Join(localPath, "client.crt"),
-			keyPath:        filepath.Join(localPath, "client.key"),
+			name:           "client.crt",
+			certPath:       CertPath(k8s, "client.crt"),
+			keyPath:        CertPath(k8s, "client.key"),
 			subject:        "minikube-user",
 			ips:            []net.IP{},
 			alternateNames: []string{},
@@ -150,8 +399,9 @@ func generateCerts(k8s KubernetesConfig) error {
 			caKeyPath:      caKeyPath,
 		},
 		{ // apiserver serving cert
-			certPath:       filepath.Join(localPath, "apiserver.crt"),
-			keyPath:        filepath.Join(localPath, "apiserver.key"),
+			name:           "apiserver.crt",
+			certPath:       CertPath(k8s, "apiserver.crt"),
+			keyPath:        CertPath(k8s, "apiserver.key"),
 			subject:        "minikube",
 			ips:            apiServerIPs,
 			alternateNames: apiServerAlternateNames,
@@ -159,8 +409,9 @@ func generateCerts(k8s KubernetesConfig) error {
 			caKeyPath:      caKeyPath,
 		},
 		{ // aggregator proxy-client cert
-			certPath:       filepath.Join(localPath, "proxy-client.crt"),
-			keyPath:        filepath.Join(localPath, "proxy-client.key"),
+			name:           "proxy-client.crt",
+			certPath:       CertPath(k8s, "proxy-client.crt"),
+			keyPath:        CertPath(k8s, "proxy-client.key"),
 			subject:        "aggregator",
 			ips:            []net.IP{},
 			alternateNames: []string{},
@@ -181,6 +432,9 @@ func generateCerts(k8s KubernetesConfig) error {
 	}
 
 	for _, signedCertSpec := range signedCertSpecs {
+		if len(names) > 0 && !containsString(names, signedCertSpec.name) {
+			continue
+		}
 		if err := util.GenerateSignedCert(
 			signedCertSpec.certPath, signedCertSpec.keyPath, signedCertSpec.subject,
 			signedCertSpec.ips, signedCertSpec.alternateNames,
@@ -192,3 +446,12 @@ func generateCerts(k8s KubernetesConfig) error {
 
 	return nil
 }
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/tests"
@@ -39,7 +41,7 @@ func TestSetupCerts(t *testing.T) {
 
 	var filesToBeTransferred []string
 	for _, cert := range certs {
-		filesToBeTransferred = append(filesToBeTransferred, filepath.Join(constants.GetMinipath(), cert))
+		filesToBeTransferred = append(filesToBeTransferred, filepath.Join(constants.GetProfilePath(constants.DefaultMachineName), cert))
 	}
 
 	if err := SetupCerts(f, k8s); err != nil {
@@ -52,3 +54,290 @@ func TestSetupCerts(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := KubernetesConfig{
+		APIServerName:  constants.APIServerName,
+		APIServerNames: []string{"example.com"},
+		DNSDomain:      constants.ClusterDNSDomain,
+		NodeIP:         "192.168.99.100",
+		ServiceCIDR:    util.DefaultServiceCIDR,
+	}
+	if err := generateCerts(k8s); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+
+	infos, err := CheckCerts(k8s)
+	if err != nil {
+		t.Fatalf("Error checking certs: %s", err)
+	}
+	if len(infos) != len(caCerts)+len(leafCerts) {
+		t.Fatalf("Expected %d certs, got %v", len(caCerts)+len(leafCerts), infos)
+	}
+	for _, c := range infos {
+		if c.IsCA != (c.Name == "ca.crt" || c.Name == "proxy-client-ca.crt") {
+			t.Errorf("Unexpected IsCA=%t for %s", c.IsCA, c.Name)
+		}
+		if c.Name == "apiserver.crt" {
+			sans := map[string]bool{}
+			for _, s := range c.SANs() {
+				sans[s] = true
+			}
+			if !sans["example.com"] || !sans["192.168.99.100"] {
+				t.Errorf("Expected apiserver SANs to contain example.com and 192.168.99.100, got %v", c.SANs())
+			}
+		}
+	}
+
+	if expiring := ExpiringCerts(infos, time.Now(), CertExpiryWarning); len(expiring) != 0 {
+		t.Errorf("Expected no expiring certs, got %v", expiring)
+	}
+	// Leaf certs are valid for a year, the CAs for ten
+	expiring := ExpiringCerts(infos, time.Now().Add(365*24*time.Hour), CertExpiryWarning)
+	if len(expiring) != len(leafCerts) {
+		t.Errorf("Expected the leaf certs to expire within a year, got %v", expiring)
+	}
+}
+
+func TestRotateCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+	}
+	if err := generateCerts(k8s); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(CertPath(k8s, name))
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", name, err)
+		}
+		return string(b)
+	}
+
+	caKey, apiserverKey := read("ca.key"), read("apiserver.key")
+	// Renewing signs the leaf certs again, like SetupCerts does
+	if err := generateCerts(k8s); err != nil {
+		t.Fatalf("Error renewing certs: %s", err)
+	}
+	if read("apiserver.key") != apiserverKey {
+		t.Errorf("Expected renew to keep the apiserver key")
+	}
+
+	if err := RotateCerts(k8s, false); err != nil {
+		t.Fatalf("Error rotating certs: %s", err)
+	}
+	if read("apiserver.key") == apiserverKey {
+		t.Errorf("Expected rotate to replace the apiserver key")
+	}
+	if read("ca.key") != caKey {
+		t.Errorf("Expected rotate to keep the CA")
+	}
+
+	if err := RotateCerts(k8s, true); err != nil {
+		t.Fatalf("Error rotating CA: %s", err)
+	}
+	if read("ca.key") == caKey {
+		t.Errorf("Expected rotate with CA to replace the CA")
+	}
+}
+
+func TestUpdateAPIServerCert(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		NodeIP:        "192.168.99.100",
+		ServiceCIDR:   util.DefaultServiceCIDR,
+	}
+	changed, err := NodeIPChanged(k8s)
+	if err != nil || changed {
+		t.Fatalf("Expected no change without certs, got %t: %v", changed, err)
+	}
+	if err := generateCerts(k8s); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+	if changed, err := NodeIPChanged(k8s); err != nil || changed {
+		t.Fatalf("Expected no change for the same IP, got %t: %v", changed, err)
+	}
+
+	client, err := ioutil.ReadFile(CertPath(k8s, "client.crt"))
+	if err != nil {
+		t.Fatalf("Error reading client cert: %s", err)
+	}
+	k8s.NodeIP = "192.168.99.101"
+	if changed, err := NodeIPChanged(k8s); err != nil || !changed {
+		t.Fatalf("Expected a change for a new IP, got %t: %v", changed, err)
+	}
+
+	f := NewFakeCommandRunner()
+	if err := UpdateAPIServerCert(f, k8s); err != nil {
+		t.Fatalf("Error updating apiserver cert: %s", err)
+	}
+	if changed, err := NodeIPChanged(k8s); err != nil || changed {
+		t.Errorf("Expected the apiserver cert to have the new IP, got %t: %v", changed, err)
+	}
+	for _, name := range []string{"apiserver.crt", "apiserver.key"} {
+		if _, err := f.GetFileToContents(CertPath(k8s, name)); err != nil {
+			t.Errorf("Cert not copied: %s", name)
+		}
+	}
+	if _, err := f.GetFileToContents(CertPath(k8s, "client.crt")); err == nil {
+		t.Errorf("Expected the client cert not to be copied")
+	}
+	if b, _ := ioutil.ReadFile(CertPath(k8s, "client.crt")); string(b) != string(client) {
+		t.Errorf("Expected the client cert to be kept")
+	}
+}
+
+func TestSharedCA(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+		SharedCA:      true,
+	}
+	if err := generateCerts(k8s); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+
+	for _, name := range []string{"ca.crt", "ca.key", "proxy-client-ca.crt"} {
+		if !util.CanReadFile(constants.MakeMiniPath(name)) {
+			t.Errorf("Expected shared %s in the minikube home", name)
+		}
+	}
+	profileDir := GetCertsDir(constants.DefaultMachineName)
+	if util.CanReadFile(filepath.Join(profileDir, "ca.crt")) {
+		t.Errorf("Expected no CA in the profile directory with a shared CA")
+	}
+	if !util.CanReadFile(filepath.Join(profileDir, "apiserver.crt")) {
+		t.Errorf("Expected the apiserver cert in the profile directory")
+	}
+
+	if err := DeleteCerts(constants.DefaultMachineName); err != nil {
+		t.Fatalf("Error deleting certs: %s", err)
+	}
+	if util.CanReadFile(filepath.Join(profileDir, "apiserver.crt")) {
+		t.Errorf("Expected the apiserver cert to be deleted")
+	}
+	if !util.CanReadFile(constants.MakeMiniPath("ca.crt")) {
+		t.Errorf("Expected the shared CA to be kept")
+	}
+}
+
+func TestMigrateLegacyCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	// Before profiles had their own certs, they were all generated in the minikube home
+	legacy := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+		SharedCA:      true,
+	}
+	if err := generateCerts(legacy); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+	for _, name := range []string{"client.crt", "client.key", "apiserver.crt", "apiserver.key", "proxy-client.crt", "proxy-client.key"} {
+		if err := os.Rename(CertPath(legacy, name), constants.MakeMiniPath(name)); err != nil {
+			t.Fatalf("Error moving %s: %s", name, err)
+		}
+	}
+
+	migrated, err := MigrateLegacyCerts(constants.DefaultMachineName)
+	if err != nil {
+		t.Fatalf("Error migrating certs: %s", err)
+	}
+	if !migrated {
+		t.Fatalf("Expected certs to be migrated")
+	}
+	profileDir := GetCertsDir(constants.DefaultMachineName)
+	for _, name := range []string{"client.crt", "client.key", "apiserver.crt", "apiserver.key", "proxy-client.crt", "proxy-client.key"} {
+		want, err := ioutil.ReadFile(constants.MakeMiniPath(name))
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", name, err)
+		}
+		got, err := ioutil.ReadFile(filepath.Join(profileDir, name))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("Expected %s to be copied into the profile: %v", name, err)
+		}
+	}
+	// The profile keeps using the CA in the minikube home as a shared CA
+	for _, name := range caCerts {
+		if util.CanReadFile(filepath.Join(profileDir, name)) {
+			t.Errorf("Expected %s not to be copied into the profile", name)
+		}
+	}
+
+	if migrated, err := MigrateLegacyCerts(constants.DefaultMachineName); err != nil || migrated {
+		t.Errorf("Expected a second migration to do nothing, got %t, %v", migrated, err)
+	}
+}
+
+func TestGenerateUserCert(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+	}
+	if _, _, err := GenerateUserCert(k8s, "jane", nil, time.Hour); err == nil {
+		t.Errorf("Expected an error without a CA")
+	}
+	if err := generateCerts(k8s); err != nil {
+		t.Fatalf("Error generating certs: %s", err)
+	}
+	for _, name := range []string{"", "../jane", "jane/doe", ".."} {
+		if _, _, err := GenerateUserCert(k8s, name, nil, time.Hour); err == nil {
+			t.Errorf("Expected an error for user %q", name)
+		}
+	}
+	if _, _, err := GenerateUserCert(k8s, "jane", []string{"../developers"}, time.Hour); err == nil {
+		t.Errorf("Expected an error for an invalid group")
+	}
+	for _, expiry := range []time.Duration{0, -time.Hour} {
+		if _, _, err := GenerateUserCert(k8s, "jane", nil, expiry); err == nil {
+			t.Errorf("Expected an error for expiry %s", expiry)
+		}
+	}
+
+	certPath, keyPath, err := GenerateUserCert(k8s, "jane", []string{"developers"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Error generating user cert: %s", err)
+	}
+	if filepath.Dir(certPath) != filepath.Join(GetCertsDir(constants.DefaultMachineName), "users") {
+		t.Errorf("Unexpected cert path %s", certPath)
+	}
+	if !util.CanReadFile(keyPath) {
+		t.Errorf("Expected key at %s", keyPath)
+	}
+	c, err := util.ReadCert(certPath)
+	if err != nil {
+		t.Fatalf("Error reading cert: %s", err)
+	}
+	if c.Subject.CommonName != "jane" || c.Issuer.CommonName != "minikubeCA" {
+		t.Errorf("Unexpected subject %s or issuer %s", c.Subject.CommonName, c.Issuer.CommonName)
+	}
+
+	if err := DeleteCerts(constants.DefaultMachineName); err != nil {
+		t.Fatalf("Error deleting certs: %s", err)
+	}
+	if util.CanReadFile(certPath) {
+		t.Errorf("Expected the user cert to be deleted")
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// These are the CNIs that can be selected with --cni
+const (
+	CNIBridge  = "bridge"
+	CNIFlannel = "flannel"
+	CNICalico  = "calico"
+)
+
+var SupportedCNIs = []string{CNIBridge, CNIFlannel, CNICalico}
+
+// cniManifests maps each CNI to the bundled manifest that deploys it, if any.
+var cniManifests = map[string]string{
+	CNIFlannel: "deploy/addons/cni/flannel.yaml",
+	CNICalico:  "deploy/addons/cni/calico.yaml",
+}
+
+// cniImages are the images that are cached and loaded for each CNI.
+var cniImages = map[string][]string{
+	CNIFlannel: {
+		"quay.io/coreos/flannel:v0.9.1-amd64",
+	},
+	CNICalico: {
+		"quay.io/calico/node:v2.6.2",
+		"quay.io/calico/cni:v1.11.0",
+	},
+}
+
+var bridgeConfTemplate = template.Must(template.New("bridgeConfTemplate").Parse(`{
+  "cniVersion": "0.3.1",
+  "name": "bridge",
+  "type": "bridge",
+  "bridge": "cni0",
+  "isDefaultGateway": true,
+  "ipMasq": true,
+  "hairpinMode": true,
+  "ipam": {
+    "type": "host-local",
+    "subnet": "{{.PodCIDR}}"
+  }
+}
+`))
+
+// ValidateCNI returns an error if the CNI isn't one minikube knows how to deploy.
+// An empty name means no CNI was selected.
+func ValidateCNI(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, c := range SupportedCNIs {
+		if c == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown CNI %s. Valid CNIs are: %v", name, SupportedCNIs)
+}
+
+// GetNetworkPlugin returns the kubelet network plugin to use, which is always cni
+// when a CNI has been selected.
+func GetNetworkPlugin(k8s KubernetesConfig) string {
+	if k8s.CNI != "" {
+		return "cni"
+	}
+	return k8s.NetworkPlugin
+}
+
+// GetCNIPodCIDR returns the pod network for the selected CNI.
+func GetCNIPodCIDR(k8s KubernetesConfig) string {
+	if k8s.PodCIDR != "" {
+		return k8s.PodCIDR
+	}
+	if k8s.CNI != "" {
+		return constants.DefaultPodCIDR
+	}
+	return ""
+}
+
+// GetCNICachedImages returns the images needed by the selected CNI.
+func GetCNICachedImages(name string) []string {
+	return cniImages[name]
+}
+
+// GetCNIAssets returns the files that have to be copied into the VM for the
+// selected CNI: a conf file for the bridge plugin, or a manifest for the addon manager.
+func GetCNIAssets(k8s KubernetesConfig) ([]assets.CopyableFile, error) {
+	if k8s.CNI == "" {
+		return nil, nil
+	}
+	if err := ValidateCNI(k8s.CNI); err != nil {
+		return nil, err
+	}
+
+	opts := struct {
+		PodCIDR string
+	}{
+		PodCIDR: GetCNIPodCIDR(k8s),
+	}
+
+	if k8s.CNI == CNIBridge {
+		b := bytes.Buffer{}
+		if err := bridgeConfTemplate.Execute(&b, opts); err != nil {
+			return nil, errors.Wrap(err, "executing bridge conf template")
+		}
+		return []assets.CopyableFile{
+			assets.NewMemoryAssetTarget(b.Bytes(), path.Join(constants.CNIConfDir, "1-k8s.conf"), "0644"),
+		}, nil
+	}
+
+	manifest := cniManifests[k8s.CNI]
+	contents, err := assets.Asset(manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s manifest", k8s.CNI)
+	}
+	t, err := template.New(k8s.CNI).Parse(string(contents))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s manifest", k8s.CNI)
+	}
+	b := bytes.Buffer{}
+	if err := t.Execute(&b, opts); err != nil {
+		return nil, errors.Wrapf(err, "executing %s manifest template", k8s.CNI)
+	}
+	return []assets.CopyableFile{
+		assets.NewMemoryAssetTarget(b.Bytes(), path.Join(constants.AddonsPath, path.Base(manifest)), "0640"),
+	}, nil
+}
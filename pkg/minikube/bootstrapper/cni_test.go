@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestGetCNIAssets(t *testing.T) {
+	tests := []struct {
+		description string
+		k8s         KubernetesConfig
+		target      string
+		contains    string
+		shouldErr   bool
+	}{
+		{
+			description: "no cni",
+		},
+		{
+			description: "bridge",
+			k8s:         KubernetesConfig{CNI: CNIBridge},
+			target:      path.Join(constants.CNIConfDir, "1-k8s.conf"),
+			contains:    `"subnet": "10.244.0.0/16"`,
+		},
+		{
+			description: "flannel with custom pod cidr",
+			k8s:         KubernetesConfig{CNI: CNIFlannel, PodCIDR: "10.1.0.0/16"},
+			target:      path.Join(constants.AddonsPath, "flannel.yaml"),
+			contains:    `"Network": "10.1.0.0/16"`,
+		},
+		{
+			description: "unknown cni",
+			k8s:         KubernetesConfig{CNI: "weave"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			files, err := GetCNIAssets(test.k8s)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if test.target == "" {
+				if len(files) != 0 {
+					t.Fatalf("Expected no files, got %d", len(files))
+				}
+				return
+			}
+			if len(files) != 1 {
+				t.Fatalf("Expected one file, got %d", len(files))
+			}
+			f := files[0]
+			if actual := path.Join(f.GetTargetDir(), f.GetTargetName()); actual != test.target {
+				t.Errorf("Expected target %s, got %s", test.target, actual)
+			}
+			contents, err := ioutil.ReadAll(f)
+			if err != nil {
+				t.Fatalf("Error reading asset: %s", err)
+			}
+			if !bytes.Contains(contents, []byte(test.contains)) {
+				t.Errorf("Expected %s to contain %s", contents, test.contains)
+			}
+		})
+	}
+}
+
+func TestGetNetworkPlugin(t *testing.T) {
+	if actual := GetNetworkPlugin(KubernetesConfig{NetworkPlugin: "kubenet"}); actual != "kubenet" {
+		t.Errorf("Expected kubenet, got %s", actual)
+	}
+	if actual := GetNetworkPlugin(KubernetesConfig{CNI: CNICalico}); actual != "cni" {
+		t.Errorf("Expected cni, got %s", actual)
+	}
+}
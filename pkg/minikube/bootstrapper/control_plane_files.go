@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// ControlPlaneComponents are the components that --control-plane-file can mount files into
+var ControlPlaneComponents = []string{"apiserver", "controller-manager", "scheduler"}
+
+// ControlPlaneFile is a file on the host that is copied into the VM. The
+// directory it is copied to is mounted into a control plane component.
+type ControlPlaneFile struct {
+	Component string
+	HostPath  string
+	VMPath    string
+}
+
+func (f ControlPlaneFile) String() string {
+	return fmt.Sprintf("%s:%s:%s", f.Component, f.HostPath, f.VMPath)
+}
+
+// ParseControlPlaneFile parses a component:hostpath:vmpath triple. The host path
+// may itself contain colons, e.g. a Windows drive letter.
+func ParseControlPlaneFile(s string) (ControlPlaneFile, error) {
+	first := strings.Index(s, ":")
+	last := strings.LastIndex(s, ":")
+	if first < 0 || first == last {
+		return ControlPlaneFile{}, fmt.Errorf("invalid control plane file %q, expected component:hostpath:vmpath", s)
+	}
+	f := ControlPlaneFile{
+		Component: s[:first],
+		HostPath:  s[first+1 : last],
+		VMPath:    s[last+1:],
+	}
+
+	valid := false
+	for _, c := range ControlPlaneComponents {
+		if c == f.Component {
+			valid = true
+		}
+	}
+	if !valid {
+		return ControlPlaneFile{}, fmt.Errorf("invalid component %q in %q. Valid components are: %v", f.Component, s, ControlPlaneComponents)
+	}
+	if f.HostPath == "" {
+		return ControlPlaneFile{}, fmt.Errorf("missing host path in %q", s)
+	}
+	if !path.IsAbs(f.VMPath) {
+		return ControlPlaneFile{}, fmt.Errorf("VM path in %q must be absolute", s)
+	}
+	if path.Dir(path.Clean(f.VMPath)) == "/" {
+		return ControlPlaneFile{}, fmt.Errorf("VM path in %q must be in a directory other than /, which is mounted into the component", s)
+	}
+
+	abs, err := filepath.Abs(f.HostPath)
+	if err != nil {
+		return ControlPlaneFile{}, errors.Wrapf(err, "getting absolute path of %s", f.HostPath)
+	}
+	f.HostPath = abs
+	return f, nil
+}
+
+// GetControlPlaneFileAssets returns the control plane files to copy into the VM.
+func GetControlPlaneFileAssets(k8s KubernetesConfig) ([]assets.CopyableFile, error) {
+	var files []assets.CopyableFile
+	for _, f := range k8s.ControlPlaneFiles {
+		a, err := assets.NewFileAsset(f.HostPath, path.Dir(f.VMPath), path.Base(f.VMPath), "0640")
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading control plane file %s", f.HostPath)
+		}
+		files = append(files, a)
+	}
+	return files, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"testing"
+)
+
+func TestParseControlPlaneFile(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  ControlPlaneFile
+		shouldErr bool
+	}{
+		{
+			input:    "apiserver:/tmp/admission.yaml:/etc/kubernetes/admission.yaml",
+			expected: ControlPlaneFile{Component: "apiserver", HostPath: "/tmp/admission.yaml", VMPath: "/etc/kubernetes/admission.yaml"},
+		},
+		{
+			input:    "scheduler:/tmp/a:b/policy.json:/etc/kubernetes/policy.json",
+			expected: ControlPlaneFile{Component: "scheduler", HostPath: "/tmp/a:b/policy.json", VMPath: "/etc/kubernetes/policy.json"},
+		},
+		{
+			input:     "kubelet:/tmp/config:/etc/kubernetes/config",
+			shouldErr: true,
+		},
+		{
+			input:     "apiserver:/etc/kubernetes/config",
+			shouldErr: true,
+		},
+		{
+			input:     "apiserver:/tmp/config:config",
+			shouldErr: true,
+		},
+		{
+			input:     "apiserver:/tmp/config:/config",
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			actual, err := ParseControlPlaneFile(test.input)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if actual != test.expected {
+				t.Errorf("Expected %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+}
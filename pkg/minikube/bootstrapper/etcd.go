@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// The maintenance operations of the etcd of the cluster
+const (
+	// EtcdDefrag releases the space of deleted keys to the filesystem
+	EtcdDefrag = "defrag"
+	// EtcdCompact drops the history of the keys before the current revision
+	EtcdCompact = "compact"
+)
+
+// The revision is a number in the output of etcdctl and a string in the
+// output of the grpc gateway, which encodes int64s as strings.
+var etcdRevisionRe = regexp.MustCompile(`"revision":"?(\d+)`)
+
+// ParseEtcdRevision returns the revision in the JSON status of an etcd member
+func ParseEtcdRevision(status string) (int64, error) {
+	m := etcdRevisionRe.FindStringSubmatch(status)
+	if m == nil {
+		return 0, fmt.Errorf("no revision in etcd status: %s", status)
+	}
+	return strconv.ParseInt(m[1], 10, 64)
+}
+
+// ValidateEtcdOperation returns an error for unknown maintenance operations
+func ValidateEtcdOperation(op string) error {
+	if op != EtcdDefrag && op != EtcdCompact {
+		return fmt.Errorf("unknown etcd operation %q, valid operations are %s and %s", op, EtcdDefrag, EtcdCompact)
+	}
+	return nil
+}
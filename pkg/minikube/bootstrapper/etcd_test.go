@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"testing"
+)
+
+func TestParseEtcdRevision(t *testing.T) {
+	var tests = []struct {
+		description string
+		status      string
+		expected    int64
+		shouldErr   bool
+	}{
+		{
+			description: "etcdctl",
+			status:      `[{"Endpoint":"127.0.0.1:2379","Status":{"header":{"cluster_id":14841639068965178418,"member_id":10276657743932975437,"revision":1234,"raft_term":2},"version":"3.1.12","dbSize":2084864}}]`,
+			expected:    1234,
+		},
+		{
+			description: "grpc gateway",
+			status:      `{"header":{"cluster_id":"14841639068965178418","member_id":"10276657743932975437","revision":"567","raft_term":"2"},"version":"3.1.10","dbSize":"1040384"}`,
+			expected:    567,
+		},
+		{
+			description: "no revision",
+			status:      `{"error":"etcdserver: request timed out"}`,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			rev, err := ParseEtcdRevision(test.status)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected an error, got revision %d", rev)
+			}
+			if rev != test.expected {
+				t.Errorf("Expected revision %d, got %d", test.expected, rev)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+// etcdctlCommand returns the etcdctl command run in the etcd container of a
+// cluster of this version.  From v1.10, kubeadm serves etcd with TLS only.
+func etcdctlCommand(version semver.Version, args string) string {
+	flags := "--endpoints=http://127.0.0.1:2379"
+	if version.GTE(semver.MustParse("1.10.0-alpha.0")) {
+		certDir := path.Join(util.DefaultCertPath, "etcd")
+		flags = fmt.Sprintf("--endpoints=https://127.0.0.1:2379 --cacert=%s --cert=%s --key=%s",
+			path.Join(certDir, "ca.crt"), path.Join(certDir, "healthcheck-client.crt"), path.Join(certDir, "healthcheck-client.key"))
+	}
+	return fmt.Sprintf("env ETCDCTL_API=3 etcdctl %s %s", flags, args)
+}
+
+// MaintainEtcd runs etcdctl in the etcd container of the cluster.
+func (k *KubeadmBootstrapper) MaintainEtcd(k8s bootstrapper.KubernetesConfig, op string) (string, error) {
+	if err := bootstrapper.ValidateEtcdOperation(op); err != nil {
+		return "", err
+	}
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing kubernetes version")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c})
+	if err != nil {
+		return "", errors.Wrap(err, "getting container runtime")
+	}
+	ids, err := cr.ListContainers("etcd")
+	if err != nil {
+		return "", errors.Wrap(err, "listing etcd containers")
+	}
+
+	// The containers include exited ones, which etcdctl can't be run in
+	var id, status string
+	for _, c := range ids {
+		out, err := k.c.CombinedOutput(cr.ContainerExec(c, etcdctlCommand(version, "endpoint status --write-out=json")))
+		if err != nil {
+			glog.Infof("Unable to get the etcd status in container %s: %s", c, err)
+			continue
+		}
+		id, status = c, out
+		break
+	}
+	if id == "" {
+		return "", fmt.Errorf("etcd is not running")
+	}
+
+	args := "defrag"
+	if op == bootstrapper.EtcdCompact {
+		rev, err := bootstrapper.ParseEtcdRevision(status)
+		if err != nil {
+			return "", errors.Wrap(err, "getting etcd revision")
+		}
+		args = fmt.Sprintf("compaction --physical %d", rev)
+	}
+	out, err := k.c.CombinedOutput(cr.ContainerExec(id, etcdctlCommand(version, args)))
+	if err != nil {
+		return "", errors.Wrapf(err, "running etcdctl %s", args)
+	}
+	return strings.TrimSpace(out), nil
+}
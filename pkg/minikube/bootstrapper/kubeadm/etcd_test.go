@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+func TestMaintainEtcd(t *testing.T) {
+	const (
+		list    = `docker ps -a --filter="name=k8s_etcd" --format="{{.ID}}"`
+		tls     = "env ETCDCTL_API=3 etcdctl --endpoints=https://127.0.0.1:2379 --cacert=/var/lib/localkube/certs/etcd/ca.crt --cert=/var/lib/localkube/certs/etcd/healthcheck-client.crt --key=/var/lib/localkube/certs/etcd/healthcheck-client.key"
+		plain   = "env ETCDCTL_API=3 etcdctl --endpoints=http://127.0.0.1:2379"
+		status  = `[{"Endpoint":"127.0.0.1:2379","Status":{"header":{"revision":1234}}}]`
+		running = "docker exec def "
+	)
+	var tests = []struct {
+		description string
+		version     string
+		op          string
+		outputs     map[string]string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "defrag",
+			version:     "v1.9.4",
+			op:          bootstrapper.EtcdDefrag,
+			outputs: map[string]string{
+				list: "def\n",
+				running + plain + " endpoint status --write-out=json": status,
+				running + plain + " defrag":                           "Finished defragmenting etcd member[http://127.0.0.1:2379]\n",
+			},
+			expected: "Finished defragmenting etcd member[http://127.0.0.1:2379]",
+		},
+		{
+			description: "compact skips exited containers",
+			version:     "v1.10.0",
+			op:          bootstrapper.EtcdCompact,
+			outputs: map[string]string{
+				list: "abc\ndef\n",
+				running + tls + " endpoint status --write-out=json": status,
+				running + tls + " compaction --physical 1234":       "compacted revision 1234\n",
+			},
+			expected: "compacted revision 1234",
+		},
+		{
+			description: "not running",
+			version:     "v1.10.0",
+			op:          bootstrapper.EtcdDefrag,
+			outputs:     map[string]string{list: ""},
+			shouldErr:   true,
+		},
+		{
+			description: "unknown operation",
+			version:     "v1.10.0",
+			op:          "snapshot",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			f := bootstrapper.NewFakeCommandRunner()
+			f.SetCommandToOutput(test.outputs)
+			k := &KubeadmBootstrapper{c: f}
+			out, err := k.MaintainEtcd(bootstrapper.KubernetesConfig{KubernetesVersion: test.version, ContainerRuntime: "docker"}, test.op)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected an error, got output %q", out)
+			}
+			if out != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, out)
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
@@ -36,6 +37,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/util"
@@ -43,6 +45,10 @@ import (
 
 type KubeadmBootstrapper struct {
 	c bootstrapper.CommandRunner
+	// registryMirrors and insecureRegistries are those of the machine config,
+	// which libmachine keeps in the engine options of the host
+	registryMirrors    []string
+	insecureRegistries []string
 }
 
 func NewKubeadmBootstrapper(api libmachine.API) (*KubeadmBootstrapper, error) {
@@ -61,9 +67,14 @@ func NewKubeadmBootstrapper(api libmachine.API) (*KubeadmBootstrapper, error) {
 		}
 		cmd = bootstrapper.NewSSHRunner(client)
 	}
-	return &KubeadmBootstrapper{
+	k := &KubeadmBootstrapper{
 		c: cmd,
-	}, nil
+	}
+	if h.HostOptions != nil && h.HostOptions.EngineOptions != nil {
+		k.registryMirrors = h.HostOptions.EngineOptions.RegistryMirror
+		k.insecureRegistries = h.HostOptions.EngineOptions.InsecureRegistry
+	}
+	return k, nil
 }
 
 //TODO(r2d4): This should most likely check the health of the apiserver
@@ -80,6 +91,12 @@ func (k *KubeadmBootstrapper) GetClusterStatus() (string, error) {
 	return "", fmt.Errorf("Error: Unrecognized output from ClusterStatus: %s", status)
 }
 
+// GetComponentStatus returns nil, the control plane runs as static pods whose
+// status the apiserver reports.
+func (k *KubeadmBootstrapper) GetComponentStatus() ([]bootstrapper.ComponentStatus, error) {
+	return nil, nil
+}
+
 // TODO(r2d4): Should this aggregate all the logs from the control plane?
 // Maybe subcommands for each component? minikube logs apiserver?
 func (k *KubeadmBootstrapper) GetClusterLogsTo(follow bool, out io.Writer) error {
@@ -133,7 +150,7 @@ func (k *KubeadmBootstrapper) StartCluster(k8s bootstrapper.KubernetesConfig) er
 }
 
 //TODO(r2d4): Split out into shared function between localkube and kubeadm
-func addAddons(files *[]assets.CopyableFile) error {
+func addAddons(files *[]assets.CopyableFile, data assets.AddonTemplateData) error {
 	// add addons to file list
 	// custom addons
 	if err := assets.AddMinikubeDirAssets(files); err != nil {
@@ -147,9 +164,11 @@ func addAddons(files *[]assets.CopyableFile) error {
 			continue
 		}
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				*files = append(*files, addon)
+			addonFiles, err := addonBundle.Files(data)
+			if err != nil {
+				return errors.Wrapf(err, "rendering addon %s", addonName)
 			}
+			*files = append(*files, addonFiles...)
 		} else if err != nil {
 			return nil
 		}
@@ -181,60 +200,239 @@ func (k *KubeadmBootstrapper) RestartCluster(k8s bootstrapper.KubernetesConfig)
 	return nil
 }
 
+// StopCluster stops the kubelet, so it doesn't restart the static pods, and
+// then the containers of the cluster with etcd last.
+func (k *KubeadmBootstrapper) StopCluster(k8s bootstrapper.KubernetesConfig) error {
+	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c})
+	if err != nil {
+		glog.Infof("Not stopping the containers of container runtime %s: %s", k8s.ContainerRuntime, err)
+		cr = nil
+	}
+	return bootstrapper.StopKubernetes(k.c, cr, func() error {
+		return k.c.Run("sudo systemctl stop kubelet")
+	}, bootstrapper.StopTimeout)
+}
+
+// ResetCluster runs kubeadm reset and removes the state it leaves behind,
+// including the etcd data.  The certs minikube generates are kept.
+func (k *KubeadmBootstrapper) ResetCluster(k8s bootstrapper.KubernetesConfig) error {
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing kubernetes version")
+	}
+	resetCmd := "sudo /usr/bin/kubeadm reset"
+	// kubeadm asks for confirmation starting with v1.11
+	if version.GTE(semver.MustParse("1.11.0-alpha.0")) {
+		resetCmd += " --force"
+	}
+	if err := k.c.Run(resetCmd); err != nil {
+		return errors.Wrapf(err, "running cmd: %s", resetCmd)
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c})
+	if err != nil {
+		glog.Infof("Not removing the containers of container runtime %s: %s", k8s.ContainerRuntime, err)
+		cr = nil
+	}
+	return bootstrapper.ResetClusterState(k.c, cr, []string{constants.KubeadmEtcdDataDir})
+}
+
 func (k *KubeadmBootstrapper) SetupCerts(k8s bootstrapper.KubernetesConfig) error {
 	return bootstrapper.SetupCerts(k.c, k8s)
 }
 
+// UpdateNodeIP signs the apiserver cert for the new IP and writes the kubeadm
+// config with the new advertise address, then reloads the control plane and
+// points kube-proxy at the new address.
+func (k *KubeadmBootstrapper) UpdateNodeIP(k8s bootstrapper.KubernetesConfig) error {
+	kubeadmCfg, err := generateConfig(k8s)
+	if err != nil {
+		return errors.Wrap(err, "generating kubeadm cfg")
+	}
+	if err := k.c.Copy(assets.NewMemoryAssetTarget([]byte(kubeadmCfg), constants.KubeadmConfigFile, "0640")); err != nil {
+		return errors.Wrap(err, "copying kubeadm cfg")
+	}
+	if err := bootstrapper.UpdateAPIServerCert(k.c, k8s); err != nil {
+		return errors.Wrap(err, "updating apiserver cert")
+	}
+	if err := k.ReloadCerts(k8s); err != nil {
+		return errors.Wrap(err, "reloading certs")
+	}
+	// The kube-proxy configmap still has the old address of the apiserver
+	if err := restartKubeProxy(k8s); err != nil {
+		return errors.Wrap(err, "restarting kube-proxy")
+	}
+	return nil
+}
+
+// ReloadCerts regenerates the kubeconfigs kubeadm signs with the CA, and restarts
+// the kubelet and the control plane containers so they use the new certs.
+func (k *KubeadmBootstrapper) ReloadCerts(k8s bootstrapper.KubernetesConfig) error {
+	// kubeadm refuses to overwrite kubeconfigs that were signed by another CA
+	if err := k.c.Run(fmt.Sprintf("sudo rm -f %s", strings.Join(constants.KubeadmKubeconfigFiles, " "))); err != nil {
+		return errors.Wrap(err, "removing kubeconfigs")
+	}
+
+	b := bytes.Buffer{}
+	if err := kubeadmRestoreTemplate.Execute(&b, struct{ KubeadmConfigFile string }{constants.KubeadmConfigFile}); err != nil {
+		return err
+	}
+	if err := k.c.Run(b.String()); err != nil {
+		return errors.Wrapf(err, "running cmd: %s", b.String())
+	}
+	if err := k.c.Run("sudo systemctl restart kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+
+	// The kubelet recreates the static pods of the control plane when their containers are killed
+	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c})
+	if err != nil {
+		return errors.Wrap(err, "getting container runtime")
+	}
+	for _, name := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler"} {
+		ids, err := cr.ListContainers(name)
+		if err != nil {
+			return errors.Wrapf(err, "listing %s containers", name)
+		}
+		if err := cr.KillContainers(ids); err != nil {
+			return errors.Wrapf(err, "killing %s containers", name)
+		}
+	}
+	return nil
+}
+
+// enableContainerRuntime starts the selected container runtime if it isn't already
+// running.  It returns nil if the runtime isn't one minikube manages.
+func (k *KubeadmBootstrapper) enableContainerRuntime(cfg bootstrapper.KubernetesConfig) (cruntime.Manager, error) {
+	cr, err := cruntime.New(cruntime.Config{
+		Type:               cfg.ContainerRuntime,
+		Runner:             k.c,
+		RegistryMirrors:    k.registryMirrors,
+		InsecureRegistries: k.insecureRegistries,
+	})
+	if err != nil {
+		glog.Infof("Not managing container runtime %s: %s", cfg.ContainerRuntime, err)
+		return nil, nil
+	}
+	if err := cr.Available(); err != nil {
+		return nil, errors.Wrapf(err, "%s can't be used as the container runtime", cr.Name())
+	}
+	if err := cr.Enable(); err != nil {
+		return nil, errors.Wrapf(err, "enabling %s", cr.Name())
+	}
+	return cr, nil
+}
+
 // SetContainerRuntime possibly sets the container runtime, if it hasn't already
-// been specified by the extra-config option.  It has a set of defaults known to
-// work for a particular runtime.
-func SetContainerRuntime(cfg map[string]string, runtime string) map[string]string {
-	if _, ok := cfg["container-runtime"]; ok {
+// been set or removed by the extra-config option.  It has a set of defaults known
+// to work for a particular runtime.
+func SetContainerRuntime(cfg map[string]string, k8s bootstrapper.KubernetesConfig) map[string]string {
+	if hasExtraOption(k8s, Kubelet, "container-runtime") {
 		glog.Infoln("Container runtime already set through extra options, ignoring --container-runtime flag.")
 		return cfg
 	}
 
+	runtime := k8s.ContainerRuntime
+
 	if runtime == "" {
 		glog.Infoln("Container runtime flag provided with no value, using defaults.")
 		return cfg
 	}
 
-	switch runtime {
-	case "crio", "cri-o":
-		cfg["container-runtime"] = "remote"
-		cfg["container-runtime-endpoint"] = "/var/run/crio/crio.sock"
-		cfg["image-service-endpoint"] = "/var/run/crio/crio.sock"
-		cfg["runtime-request-timeout"] = "15m"
-	default:
+	cr, err := cruntime.New(cruntime.Config{Type: runtime})
+	if err != nil {
+		// Pass runtimes minikube doesn't manage straight through to the kubelet
 		cfg["container-runtime"] = runtime
+		return cfg
+	}
+	for k, v := range cr.KubeletOptions() {
+		cfg[k] = v
 	}
 
 	return cfg
 }
 
-// NewKubeletConfig generates a new systemd unit containing a configured kubelet
-// based on the options present in the KubernetesConfig.
-func NewKubeletConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
-	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+// SetNetworkPlugin possibly sets the kubelet network plugin, if it hasn't already
+// been set or removed by the extra-config option.  Selecting a CNI always uses the
+// cni plugin with the directories the CNI manifests install into.
+func SetNetworkPlugin(cfg map[string]string, k8s bootstrapper.KubernetesConfig) map[string]string {
+	if hasExtraOption(k8s, Kubelet, "network-plugin") {
+		glog.Infoln("Network plugin already set through extra options, ignoring --network-plugin and --cni flags.")
+		return cfg
+	}
+
+	plugin := bootstrapper.GetNetworkPlugin(k8s)
+	if plugin == "" {
+		return cfg
+	}
+	cfg["network-plugin"] = plugin
+	if plugin == "cni" {
+		cfg["cni-conf-dir"] = constants.CNIConfDir
+		cfg["cni-bin-dir"] = constants.CNIBinDir
+	}
+
+	return cfg
+}
+
+// SetClusterDNS points the kubelet at the DNS IP of the service CIDR, unless
+// cluster-dns was set or removed by the extra-config option.
+func SetClusterDNS(cfg map[string]string, k8s bootstrapper.KubernetesConfig) (map[string]string, error) {
+	if hasExtraOption(k8s, Kubelet, "cluster-dns") {
+		glog.Infoln("Cluster DNS already set through extra options, ignoring --service-cluster-ip-range.")
+		return cfg, nil
+	}
+
+	dnsIP, err := util.GetDNSIP(bootstrapper.GetServiceCIDR(k8s))
 	if err != nil {
-		return "", errors.Wrap(err, "parsing kubernetes version")
+		return nil, errors.Wrap(err, "getting cluster dns ip")
+	}
+	cfg["cluster-dns"] = dnsIP.String()
+	return cfg, nil
+}
+
+// hasExtraOption returns whether the extra-config option sets or removes a flag of a component
+func hasExtraOption(k8s bootstrapper.KubernetesConfig, component, key string) bool {
+	for _, opt := range k8s.ExtraOptions {
+		if opt.Component == component && opt.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNodeReservations sets the resources the kubelet reserves for the node
+// resource preset, except those set or removed by the extra-config option.
+func SetNodeReservations(cfg map[string]string, k8s bootstrapper.KubernetesConfig) (map[string]string, error) {
+	reservations, err := bootstrapper.GetNodeReservations(k8s)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting node reservations")
 	}
+	for k, v := range reservations {
+		if hasExtraOption(k8s, Kubelet, k) {
+			glog.Infof("%s already set through extra options, ignoring --node-resource-preset for it.", k)
+			continue
+		}
+		cfg[k] = v
+	}
+	return cfg, nil
+}
 
-	extraOpts, err := ExtraConfigForComponent(Kubelet, k8s.ExtraOptions, version)
+// NewKubeletConfig generates a new systemd unit containing a configured kubelet
+// based on the options present in the KubernetesConfig.  Options that can be set
+// in the KubeletConfiguration file are left out for versions that read it.
+func NewKubeletConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
+	extraOpts, _, err := kubeletOptions(k8s)
 	if err != nil {
-		return "", errors.Wrap(err, "generating extra configuration for kubelet")
+		return "", err
 	}
 
-	extraOpts = SetContainerRuntime(extraOpts, k8s.ContainerRuntime)
 	extraFlags := convertToFlags(extraOpts)
 	b := bytes.Buffer{}
 	opts := struct {
 		ExtraOptions     string
-		FeatureGates     string
 		ContainerRuntime string
 	}{
 		ExtraOptions:     extraFlags,
-		FeatureGates:     k8s.FeatureGates,
 		ContainerRuntime: k8s.ContainerRuntime,
 	}
 	if err := kubeletSystemdTemplate.Execute(&b, opts); err != nil {
@@ -245,9 +443,14 @@ func NewKubeletConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 }
 
 func (k *KubeadmBootstrapper) UpdateCluster(cfg bootstrapper.KubernetesConfig) error {
-	if cfg.ShouldLoadCachedImages {
+	cr, err := k.enableContainerRuntime(cfg)
+	if err != nil {
+		return errors.Wrap(err, "enabling container runtime")
+	}
+	if cfg.ShouldLoadCachedImages && cr != nil {
 		// Make best effort to load any cached images
-		go machine.LoadImages(k.c, constants.GetKubeadmCachedImages(cfg.KubernetesVersion), constants.ImageCacheDir)
+		images := append(constants.GetKubeadmCachedImages(cfg.KubernetesVersion), bootstrapper.GetCNICachedImages(cfg.CNI)...)
+		go machine.LoadImages(k.c, cr, images, constants.ImageCacheDir)
 	}
 	kubeadmCfg, err := generateConfig(cfg)
 	if err != nil {
@@ -265,6 +468,14 @@ func (k *KubeadmBootstrapper) UpdateCluster(cfg bootstrapper.KubernetesConfig) e
 		assets.NewMemoryAssetTarget([]byte(kubeadmCfg), constants.KubeadmConfigFile, "0640"),
 	}
 
+	kubeletCfgFile, err := NewKubeletConfigFile(cfg)
+	if err != nil {
+		return errors.Wrap(err, "generating kubelet config file")
+	}
+	if kubeletCfgFile != "" {
+		files = append(files, assets.NewMemoryAssetTarget([]byte(kubeletCfgFile), constants.KubeletConfigFile, "0640"))
+	}
+
 	var g errgroup.Group
 	for _, bin := range []string{"kubelet", "kubeadm"} {
 		bin := bin
@@ -287,10 +498,38 @@ func (k *KubeadmBootstrapper) UpdateCluster(cfg bootstrapper.KubernetesConfig) e
 		return errors.Wrap(err, "downloading binaries")
 	}
 
-	if err := addAddons(&files); err != nil {
+	addonData, err := assets.NewAddonTemplateData(cfg.ServiceCIDR)
+	if err != nil {
+		return errors.Wrap(err, "getting addon template data")
+	}
+	if err := addAddons(&files, addonData); err != nil {
 		return errors.Wrap(err, "adding addons to copyable files")
 	}
 
+	cniFiles, err := bootstrapper.GetCNIAssets(cfg)
+	if err != nil {
+		return errors.Wrap(err, "adding cni files")
+	}
+	files = append(files, cniFiles...)
+
+	controlPlaneFiles, err := bootstrapper.GetControlPlaneFileAssets(cfg)
+	if err != nil {
+		return errors.Wrap(err, "adding control plane files")
+	}
+	files = append(files, controlPlaneFiles...)
+
+	auditPolicy, err := bootstrapper.GetAuditPolicyAsset(cfg)
+	if err != nil {
+		return errors.Wrap(err, "adding audit policy")
+	}
+	if auditPolicy != nil {
+		files = append(files, auditPolicy)
+	}
+
+	if err := k.migrateEtcdDataDir(cr); err != nil {
+		return errors.Wrap(err, "migrating etcd data")
+	}
+
 	for _, f := range files {
 		if err := k.c.Copy(f); err != nil {
 			return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
@@ -309,6 +548,75 @@ sudo systemctl start kubelet
 	return nil
 }
 
+// migrateEtcdDataDir moves the etcd data of a running cluster from the data dir
+// of older minikube versions to the persistent one.  etcd is stopped first, the
+// etcd manifest is regenerated with the new data dir when the cluster restarts.
+func (k *KubeadmBootstrapper) migrateEtcdDataDir(cr cruntime.Manager) error {
+	out, err := k.c.CombinedOutput(fmt.Sprintf("if [ -d %s/member ] && [ ! -d %s/member ]; then echo migrate; fi",
+		constants.LegacyKubeadmEtcdDataDir, constants.KubeadmEtcdDataDir))
+	if err != nil {
+		return errors.Wrap(err, "checking etcd data dir")
+	}
+	if strings.TrimSpace(out) != "migrate" {
+		return nil
+	}
+
+	glog.Infof("Moving etcd data from %s to %s", constants.LegacyKubeadmEtcdDataDir, constants.KubeadmEtcdDataDir)
+	if err := k.c.Run("sudo rm -f /etc/kubernetes/manifests/etcd.yaml"); err != nil {
+		return errors.Wrap(err, "removing etcd manifest")
+	}
+	if cr != nil {
+		ids, err := cr.ListContainers("etcd")
+		if err != nil {
+			return errors.Wrap(err, "listing etcd containers")
+		}
+		if err := cr.StopContainers(ids); err != nil {
+			return errors.Wrap(err, "stopping etcd")
+		}
+	}
+	return k.c.Run(fmt.Sprintf("sudo mkdir -p %s && sudo mv %s/member %s/",
+		constants.KubeadmEtcdDataDir, constants.LegacyKubeadmEtcdDataDir, constants.KubeadmEtcdDataDir))
+}
+
+// controlPlaneExtraOptions returns the extra options of the control plane components.
+// Options minikube sets for features such as audit logging come first so
+// they can still be overridden with --extra-config.
+func controlPlaneExtraOptions(k8s bootstrapper.KubernetesConfig) util.ExtraOptionSlice {
+	var extraOpts util.ExtraOptionSlice
+	for k, v := range bootstrapper.GetAuditAPIServerOptions(k8s) {
+		extraOpts = append(extraOpts, util.ExtraOption{Component: Apiserver, Key: k, Value: v})
+	}
+	return append(extraOpts, k8s.ExtraOptions...)
+}
+
+// ComponentOptions returns the flags minikube generates for a component, after
+// applying the version specific defaults and the user's extra options.  For
+// kubelets that read a KubeletConfiguration file, it includes the options that
+// are written to the file.
+func ComponentOptions(component string, k8s bootstrapper.KubernetesConfig) (map[string]string, error) {
+	if _, ok := componentToKubeadmConfigKey[component]; !ok {
+		return nil, fmt.Errorf("Unknown component %s. Valid components are %v", component, Components())
+	}
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing kubernetes version")
+	}
+	if component == Kubelet {
+		return kubeletExtraOptions(k8s, version)
+	}
+
+	opts, err := ExtraConfigForComponent(component, controlPlaneExtraOptions(k8s), version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "generating extra configuration for %s", component)
+	}
+	gates, err := util.ParseFeatureGates(k8s.FeatureGates)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing feature gates")
+	}
+	setFeatureGates(opts, component, gates, version)
+	return opts, nil
+}
+
 func generateConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
 	if err != nil {
@@ -316,29 +624,39 @@ func generateConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 	}
 
 	// generates a map of component to extra args for apiserver, controller-manager, and scheduler
-	extraComponentConfig, err := NewComponentExtraArgs(k8s.ExtraOptions, version, k8s.FeatureGates)
+	extraComponentConfig, err := NewComponentExtraArgs(controlPlaneExtraOptions(k8s), version, k8s.FeatureGates, k8s.ForceExtraOptions)
 	if err != nil {
 		return "", errors.Wrap(err, "generating extra component config for kubeadm")
 	}
+	etcdExtraArgs, err := ExtraConfigForComponent(Etcd, k8s.ExtraOptions, version)
+	if err != nil {
+		return "", errors.Wrap(err, "generating extra etcd config for kubeadm")
+	}
 
 	opts := struct {
 		CertDir           string
 		ServiceCIDR       string
+		PodSubnet         string
 		AdvertiseAddress  string
 		APIServerPort     int
 		KubernetesVersion string
 		EtcdDataDir       string
+		EtcdExtraArgs     map[string]string
 		NodeName          string
 		ExtraArgs         []ComponentExtraArgs
+		ExtraVolumes      []ComponentExtraVolumes
 	}{
 		CertDir:           util.DefaultCertPath,
-		ServiceCIDR:       util.DefaultServiceCIDR,
+		ServiceCIDR:       bootstrapper.GetServiceCIDR(k8s),
+		PodSubnet:         bootstrapper.GetCNIPodCIDR(k8s),
 		AdvertiseAddress:  k8s.NodeIP,
-		APIServerPort:     util.APIServerPort,
+		APIServerPort:     bootstrapper.GetAPIServerPort(k8s),
 		KubernetesVersion: k8s.KubernetesVersion,
-		EtcdDataDir:       "/data", //TODO(r2d4): change to something else persisted
+		EtcdDataDir:       constants.KubeadmEtcdDataDir,
+		EtcdExtraArgs:     etcdExtraArgs,
 		NodeName:          k8s.NodeName,
 		ExtraArgs:         extraComponentConfig,
+		ExtraVolumes:      NewComponentExtraVolumes(k8s),
 	}
 
 	b := bytes.Buffer{}
@@ -353,12 +671,19 @@ func maybeDownloadAndCache(binary, version string) (string, error) {
 	targetDir := constants.MakeMiniPath("cache", version)
 	targetFilepath := path.Join(targetDir, binary)
 
+	checksumURL := constants.GetKubernetesReleaseURLSha256(binary, version)
 	_, err := os.Stat(targetFilepath)
-	// If it exists, do no verification and continue
 	if err == nil {
-		return targetFilepath, nil
-	}
-	if !os.IsNotExist(err) {
+		// If it exists, only use it if it matches the published checksum
+		verifyErr := util.VerifyCachedFile(targetFilepath, checksumURL)
+		if verifyErr == nil {
+			return targetFilepath, nil
+		}
+		glog.Infof("Cached %s %s failed verification, downloading again: %s", binary, version, verifyErr)
+		if err := util.RemoveCachedFile(targetFilepath); err != nil {
+			return "", errors.Wrapf(err, "removing cached %s", binary)
+		}
+	} else if !os.IsNotExist(err) {
 		return "", errors.Wrapf(err, "stat %s version %s at %s", binary, version, targetDir)
 	}
 
@@ -371,13 +696,16 @@ func maybeDownloadAndCache(binary, version string) (string, error) {
 		Mkdirs: download.MkdirAll,
 	}
 
-	options.Checksum = constants.GetKubernetesReleaseURLSha1(binary, version)
-	options.ChecksumHash = crypto.SHA1
+	options.Checksum = checksumURL
+	options.ChecksumHash = crypto.SHA256
 
 	fmt.Printf("Downloading %s %s\n", binary, version)
 	if err := download.ToFile(url, targetFilepath, options); err != nil {
 		return "", errors.Wrapf(err, "Error downloading %s %s", binary, version)
 	}
+	if err := util.WriteChecksumFile(targetFilepath); err != nil {
+		return "", errors.Wrapf(err, "writing checksum for %s %s", binary, version)
+	}
 	fmt.Printf("Finished Downloading %s %s\n", binary, version)
 
 	return targetFilepath, nil
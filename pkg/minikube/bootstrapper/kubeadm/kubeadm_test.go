@@ -47,7 +47,88 @@ certificatesDir: /var/lib/localkube/certs/
 networking:
   serviceSubnet: 10.96.0.0/12
 etcd:
-  dataDir: /data
+  dataDir: /var/lib/localkube/kubeadm/etcd
+nodeName: minikube
+`,
+		},
+		{
+			description: "apiserver port",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				KubernetesVersion: "v1.8.0",
+				NodeName:          "minikube",
+				APIServerPort:     6443,
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 6443
+kubernetesVersion: v1.8.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
+nodeName: minikube
+`,
+		},
+		{
+			description: "etcd extra args",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				KubernetesVersion: "v1.8.0",
+				NodeName:          "minikube",
+				FeatureGates:      "HugePages=true",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Etcd, Key: "quota-backend-bytes", Value: "4294967296"},
+					util.ExtraOption{Component: Etcd, Key: "snapshot-count", Value: "5000"},
+				},
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 8443
+kubernetesVersion: v1.8.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
+  extraArgs:
+    quota-backend-bytes: "4294967296"
+    snapshot-count: "5000"
+nodeName: minikube
+apiServerExtraArgs:
+  feature-gates: "HugePages=true"
+controllerManagerExtraArgs:
+  feature-gates: "HugePages=true"
+schedulerExtraArgs:
+  feature-gates: "HugePages=true"
+`,
+		},
+		{
+			description: "service and pod cidrs",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				KubernetesVersion: "v1.8.0",
+				NodeName:          "minikube",
+				ServiceCIDR:       "172.20.0.0/16",
+				PodCIDR:           "172.21.0.0/16",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.100
+  bindPort: 8443
+kubernetesVersion: v1.8.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 172.20.0.0/16
+  podSubnet: 172.21.0.0/16
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
 nodeName: minikube
 `,
 		},
@@ -60,7 +141,7 @@ nodeName: minikube
 				ExtraOptions: util.ExtraOptionSlice{
 					util.ExtraOption{
 						Component: Apiserver,
-						Key:       "fail-no-swap",
+						Key:       "enable-swagger-ui",
 						Value:     "true",
 					},
 					util.ExtraOption{
@@ -85,10 +166,10 @@ certificatesDir: /var/lib/localkube/certs/
 networking:
   serviceSubnet: 10.96.0.0/12
 etcd:
-  dataDir: /data
+  dataDir: /var/lib/localkube/kubeadm/etcd
 nodeName: extra-args-minikube
 apiServerExtraArgs:
-  fail-no-swap: "true"
+  enable-swagger-ui: "true"
 controllerManagerExtraArgs:
   kube-api-burst: "32"
 schedulerExtraArgs:
@@ -104,12 +185,12 @@ schedulerExtraArgs:
 				ExtraOptions: util.ExtraOptionSlice{
 					util.ExtraOption{
 						Component: Apiserver,
-						Key:       "fail-no-swap",
+						Key:       "enable-swagger-ui",
 						Value:     "true",
 					},
 					util.ExtraOption{
 						Component: Apiserver,
-						Key:       "kube-api-burst",
+						Key:       "max-requests-inflight",
 						Value:     "32",
 					},
 				},
@@ -124,11 +205,11 @@ certificatesDir: /var/lib/localkube/certs/
 networking:
   serviceSubnet: 10.96.0.0/12
 etcd:
-  dataDir: /data
+  dataDir: /var/lib/localkube/kubeadm/etcd
 nodeName: extra-args-minikube
 apiServerExtraArgs:
-  fail-no-swap: "true"
-  kube-api-burst: "32"
+  enable-swagger-ui: "true"
+  max-requests-inflight: "32"
 `,
 		},
 		{
@@ -149,7 +230,7 @@ certificatesDir: /var/lib/localkube/certs/
 networking:
   serviceSubnet: 10.96.0.0/12
 etcd:
-  dataDir: /data
+  dataDir: /var/lib/localkube/kubeadm/etcd
 nodeName: extra-args-minikube
 apiServerExtraArgs:
   feature-gates: "HugePages=true,OtherFeature=false"
@@ -169,7 +250,7 @@ schedulerExtraArgs:
 				ExtraOptions: util.ExtraOptionSlice{
 					util.ExtraOption{
 						Component: Apiserver,
-						Key:       "fail-no-swap",
+						Key:       "enable-swagger-ui",
 						Value:     "true",
 					},
 				},
@@ -184,10 +265,10 @@ certificatesDir: /var/lib/localkube/certs/
 networking:
   serviceSubnet: 10.96.0.0/12
 etcd:
-  dataDir: /data
+  dataDir: /var/lib/localkube/kubeadm/etcd
 nodeName: extra-args-minikube
 apiServerExtraArgs:
-  fail-no-swap: "true"
+  enable-swagger-ui: "true"
   feature-gates: "HugePages=true,OtherFeature=false"
 controllerManagerExtraArgs:
   feature-gates: "HugePages=true,OtherFeature=false"
@@ -195,6 +276,168 @@ schedulerExtraArgs:
   feature-gates: "HugePages=true,OtherFeature=false"
 `,
 		},
+		{
+			description: "cni",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: "v1.8.0",
+				NodeName:          "minikube",
+				CNI:               "flannel",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.101
+  bindPort: 8443
+kubernetesVersion: v1.8.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+  podSubnet: 10.244.0.0/16
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
+nodeName: minikube
+`,
+		},
+		{
+			description: "audit policy",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: "v1.8.0",
+				NodeName:          "minikube",
+				AuditPolicy:       "metadata",
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.101
+  bindPort: 8443
+kubernetesVersion: v1.8.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
+nodeName: minikube
+apiServerExtraArgs:
+  audit-log-path: "-"
+  audit-policy-file: "/etc/kubernetes/audit/policy.yaml"
+apiServerExtraVolumes:
+- name: audit-policy
+  hostPath: /etc/kubernetes/audit
+  mountPath: /etc/kubernetes/audit
+`,
+		},
+		{
+			description: "control plane files",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: "v1.8.0",
+				NodeName:          "minikube",
+				AuditPolicy:       "metadata",
+				ControlPlaneFiles: []bootstrapper.ControlPlaneFile{
+					{Component: Scheduler, HostPath: "/tmp/policy.json", VMPath: "/etc/kubernetes/extra/policy.json"},
+					{Component: Apiserver, HostPath: "/tmp/encryption.yaml", VMPath: "/etc/kubernetes/extra/encryption.yaml"},
+					{Component: Apiserver, HostPath: "/tmp/oidc-ca.crt", VMPath: "/etc/kubernetes/extra/oidc-ca.crt"},
+					{Component: Apiserver, HostPath: "/tmp/audit-webhook.yaml", VMPath: "/etc/kubernetes/audit/webhook.yaml"},
+					{Component: Apiserver, HostPath: "/tmp/admission.yaml", VMPath: "/etc/kubernetes/admission/admission.yaml"},
+				},
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.101
+  bindPort: 8443
+kubernetesVersion: v1.8.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
+nodeName: minikube
+apiServerExtraArgs:
+  audit-log-path: "-"
+  audit-policy-file: "/etc/kubernetes/audit/policy.yaml"
+apiServerExtraVolumes:
+- name: audit-policy
+  hostPath: /etc/kubernetes/audit
+  mountPath: /etc/kubernetes/audit
+- name: control-plane-files-0
+  hostPath: /etc/kubernetes/extra
+  mountPath: /etc/kubernetes/extra
+- name: control-plane-files-1
+  hostPath: /etc/kubernetes/admission
+  mountPath: /etc/kubernetes/admission
+schedulerExtraVolumes:
+- name: control-plane-files-0
+  hostPath: /etc/kubernetes/extra
+  mountPath: /etc/kubernetes/extra
+`,
+		},
+		{
+			description: "misspelled flag",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: "v1.9.0",
+				NodeName:          "minikube",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{
+						Component: Apiserver,
+						Key:       "admision-control",
+						Value:     "NamespaceLifecycle",
+					},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "forced misspelled flag",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: "v1.9.0",
+				NodeName:          "minikube",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{
+						Component: Apiserver,
+						Key:       "admision-control",
+						Value:     "NamespaceLifecycle",
+					},
+				},
+				ForceExtraOptions: true,
+			},
+			expectedCfg: `apiVersion: kubeadm.k8s.io/v1alpha1
+kind: MasterConfiguration
+api:
+  advertiseAddress: 192.168.1.101
+  bindPort: 8443
+kubernetesVersion: v1.9.0
+certificatesDir: /var/lib/localkube/certs/
+networking:
+  serviceSubnet: 10.96.0.0/12
+etcd:
+  dataDir: /var/lib/localkube/kubeadm/etcd
+nodeName: minikube
+apiServerExtraArgs:
+  admision-control: "NamespaceLifecycle"
+  admission-control: "Initializers,NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,DefaultTolerationSeconds,NodeRestriction,ResourceQuota,MutatingAdmissionWebhook"
+`,
+		},
+		{
+			description: "flag added in a later version",
+			cfg: bootstrapper.KubernetesConfig{
+				NodeIP:            "192.168.1.101",
+				KubernetesVersion: "v1.9.0",
+				NodeName:          "minikube",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{
+						Component: Apiserver,
+						Key:       "enable-admission-plugins",
+						Value:     "NamespaceLifecycle",
+					},
+				},
+			},
+			shouldErr: true,
+		},
 		{
 			// Unknown components should fail silently
 			description: "unknown component",
@@ -232,3 +475,143 @@ schedulerExtraArgs:
 		})
 	}
 }
+
+func TestComponentOptions(t *testing.T) {
+	tests := []struct {
+		description string
+		component   string
+		cfg         bootstrapper.KubernetesConfig
+		expected    map[string]string
+		absent      []string
+		shouldErr   bool
+	}{
+		{
+			description: "kubelet defaults",
+			component:   Kubelet,
+			cfg:         bootstrapper.KubernetesConfig{KubernetesVersion: "v1.9.0"},
+			expected:    map[string]string{"cadvisor-port": "0", "hostname-override": "minikube", "cluster-dns": "10.96.0.10"},
+		},
+		{
+			description: "kubelet cluster dns from the service cidr",
+			component:   Kubelet,
+			cfg:         bootstrapper.KubernetesConfig{KubernetesVersion: "v1.9.0", ServiceCIDR: "fd00:10:96::/108"},
+			expected:    map[string]string{"cluster-dns": "fd00:10:96::a"},
+		},
+		{
+			description: "kubelet cluster dns from extra config",
+			component:   Kubelet,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				ServiceCIDR:       "172.20.0.0/16",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "cluster-dns", Value: "172.20.0.53"},
+				},
+			},
+			expected: map[string]string{"cluster-dns": "172.20.0.53"},
+		},
+		{
+			description: "kubelet node reservations",
+			component:   Kubelet,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion:  "v1.9.0",
+				NodeResourcePreset: bootstrapper.NodeResourcePresetBalanced,
+				NodeMemory:         4096,
+				NodeCPUs:           2,
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "system-reserved", Remove: true},
+				},
+			},
+			expected: map[string]string{
+				"kube-reserved": "cpu=100m,memory=204Mi",
+				"eviction-hard": "memory.available<204Mi,nodefs.available<10%,nodefs.inodesFree<5%,imagefs.available<15%",
+			},
+			absent: []string{"system-reserved"},
+		},
+		{
+			description: "component feature gates",
+			component:   Kubelet,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				FeatureGates:      "CustomResourceValidation=true,kubelet:DevicePlugins=true",
+			},
+			expected: map[string]string{"feature-gates": "DevicePlugins=true"},
+		},
+		{
+			description: "apiserver feature gates",
+			component:   Apiserver,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				FeatureGates:      "CustomResourceValidation=true,kubelet:DevicePlugins=true",
+			},
+			expected: map[string]string{"feature-gates": "CustomResourceValidation=true"},
+		},
+		{
+			description: "remove kubelet default",
+			component:   Kubelet,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "cadvisor-port", Remove: true},
+				},
+			},
+			expected: map[string]string{"hostname-override": "minikube"},
+			absent:   []string{"cadvisor-port"},
+		},
+		{
+			description: "remove kubelet runtime and network plugin",
+			component:   Kubelet,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				ContainerRuntime:  "rkt",
+				NetworkPlugin:     "cni",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "container-runtime", Remove: true},
+					util.ExtraOption{Component: Kubelet, Key: "network-plugin", Remove: true},
+				},
+			},
+			expected: map[string]string{"hostname-override": "minikube"},
+			absent:   []string{"container-runtime", "network-plugin"},
+		},
+		{
+			description: "remove apiserver default",
+			component:   Apiserver,
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Apiserver, Key: "admission-control", Remove: true},
+					util.ExtraOption{Component: Apiserver, Key: "v", Value: "5"},
+				},
+			},
+			expected: map[string]string{"v": "5"},
+			absent:   []string{"admission-control"},
+		},
+		{
+			description: "unknown component",
+			component:   "proxy",
+			cfg:         bootstrapper.KubernetesConfig{KubernetesVersion: "v1.9.0"},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			opts, err := ComponentOptions(test.component, test.cfg)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none: %v", opts)
+			}
+			for k, v := range test.expected {
+				if opts[k] != v {
+					t.Errorf("Expected %s=%s, got %v", k, v, opts)
+				}
+			}
+			for _, k := range test.absent {
+				if _, ok := opts[k]; ok {
+					t.Errorf("Expected %s to be removed, got %v", k, opts)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// The kubelet reads its configuration from a KubeletConfiguration file
+// passed with --config starting with this version.
+var kubeletConfigFileVersion = semver.MustParse("1.10.0-alpha.0")
+
+const kubeletConfigAPIVersion = "kubelet.config.k8s.io/v1beta1"
+
+// How the value of a flag is converted into a config file field
+type kubeletFieldType int
+
+const (
+	stringField kubeletFieldType = iota
+	boolField
+	intField
+	// comma separated list, e.g. --cluster-dns=10.0.0.10,10.0.0.11
+	listField
+	// comma separated key=value pairs, e.g. --kube-reserved=cpu=100m,memory=100Mi
+	mapField
+	// comma separated key<value pairs, e.g. --eviction-hard=memory.available<100Mi
+	evictionField
+	// comma separated key=bool pairs, e.g. --feature-gates=Foo=true
+	featureGateField
+)
+
+type kubeletField struct {
+	// path of the field in the KubeletConfiguration, "." separated
+	path string
+	kind kubeletFieldType
+}
+
+// kubeletFlagToConfigField maps kubelet flags onto their KubeletConfiguration fields.
+// Flags that aren't listed here have no config file equivalent and stay flags.
+var kubeletFlagToConfigField = map[string]kubeletField{
+	"address":                           {"address", stringField},
+	"anonymous-auth":                    {"authentication.anonymous.enabled", boolField},
+	"authentication-token-webhook":      {"authentication.webhook.enabled", boolField},
+	"authorization-mode":                {"authorization.mode", stringField},
+	"cgroup-driver":                     {"cgroupDriver", stringField},
+	"cgroup-root":                       {"cgroupRoot", stringField},
+	"cgroups-per-qos":                   {"cgroupsPerQOS", boolField},
+	"client-ca-file":                    {"authentication.x509.clientCAFile", stringField},
+	"cluster-dns":                       {"clusterDNS", listField},
+	"cluster-domain":                    {"clusterDomain", stringField},
+	"cpu-manager-policy":                {"cpuManagerPolicy", stringField},
+	"enforce-node-allocatable":          {"enforceNodeAllocatable", listField},
+	"eviction-hard":                     {"evictionHard", evictionField},
+	"eviction-soft":                     {"evictionSoft", evictionField},
+	"fail-swap-on":                      {"failSwapOn", boolField},
+	"feature-gates":                     {"featureGates", featureGateField},
+	"file-check-frequency":              {"fileCheckFrequency", stringField},
+	"hairpin-mode":                      {"hairpinMode", stringField},
+	"healthz-bind-address":              {"healthzBindAddress", stringField},
+	"healthz-port":                      {"healthzPort", intField},
+	"image-gc-high-threshold":           {"imageGCHighThresholdPercent", intField},
+	"image-gc-low-threshold":            {"imageGCLowThresholdPercent", intField},
+	"kube-api-burst":                    {"kubeAPIBurst", intField},
+	"kube-api-qps":                      {"kubeAPIQPS", intField},
+	"kube-reserved":                     {"kubeReserved", mapField},
+	"max-open-files":                    {"maxOpenFiles", intField},
+	"max-pods":                          {"maxPods", intField},
+	"pod-cidr":                          {"podCIDR", stringField},
+	"pod-manifest-path":                 {"staticPodPath", stringField},
+	"pods-per-core":                     {"podsPerCore", intField},
+	"port":                              {"port", intField},
+	"read-only-port":                    {"readOnlyPort", intField},
+	"resolv-conf":                       {"resolvConf", stringField},
+	"rotate-certificates":               {"rotateCertificates", boolField},
+	"runtime-request-timeout":           {"runtimeRequestTimeout", stringField},
+	"serialize-image-pulls":             {"serializeImagePulls", boolField},
+	"streaming-connection-idle-timeout": {"streamingConnectionIdleTimeout", stringField},
+	"sync-frequency":                    {"syncFrequency", stringField},
+	"system-reserved":                   {"systemReserved", mapField},
+	"tls-cert-file":                     {"tlsCertFile", stringField},
+	"tls-private-key-file":              {"tlsPrivateKeyFile", stringField},
+}
+
+// supportsKubeletConfigFile returns whether the kubelet of this version is
+// configured through a KubeletConfiguration file.
+func supportsKubeletConfigFile(version semver.Version) bool {
+	return version.GTE(kubeletConfigFileVersion)
+}
+
+// splitKubeletOptions moves the kubelet flags that have a KubeletConfiguration
+// equivalent into a config, returning the remaining flags and the config.
+func splitKubeletOptions(opts map[string]string) (map[string]string, map[string]interface{}, error) {
+	flags := map[string]string{}
+	config := map[string]interface{}{}
+	for k, v := range opts {
+		field, ok := kubeletFlagToConfigField[k]
+		if !ok {
+			flags[k] = v
+			continue
+		}
+		value, err := convertKubeletValue(field.kind, v)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "converting kubelet option %s=%s", k, v)
+		}
+		setNestedField(config, strings.Split(field.path, "."), value)
+	}
+	return flags, config, nil
+}
+
+func convertKubeletValue(kind kubeletFieldType, v string) (interface{}, error) {
+	switch kind {
+	case boolField:
+		return strconv.ParseBool(v)
+	case intField:
+		return strconv.Atoi(v)
+	case listField:
+		return strings.Split(v, ","), nil
+	case mapField, evictionField, featureGateField:
+		sep := "="
+		if kind == evictionField {
+			sep = "<"
+		}
+		m := map[string]interface{}{}
+		for _, pair := range strings.Split(v, ",") {
+			kv := strings.SplitN(pair, sep, 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("expected key%svalue, got %q", sep, pair)
+			}
+			if kind == featureGateField {
+				b, err := strconv.ParseBool(kv[1])
+				if err != nil {
+					return nil, errors.Wrapf(err, "feature gate %s", kv[0])
+				}
+				m[kv[0]] = b
+				continue
+			}
+			m[kv[0]] = kv[1]
+		}
+		return m, nil
+	default:
+		return v, nil
+	}
+}
+
+func setNestedField(config map[string]interface{}, path []string, value interface{}) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := config[p].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			config[p] = next
+		}
+		config = next
+	}
+	config[path[len(path)-1]] = value
+}
+
+// renderKubeletConfig renders a KubeletConfiguration with apiVersion and kind first.
+func renderKubeletConfig(config map[string]interface{}) (string, error) {
+	doc := yaml.MapSlice{
+		{Key: "apiVersion", Value: kubeletConfigAPIVersion},
+		{Key: "kind", Value: "KubeletConfiguration"},
+	}
+	keys := []string{}
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		doc = append(doc, yaml.MapItem{Key: k, Value: config[k]})
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling kubelet config")
+	}
+	return string(b), nil
+}
+
+// kubeletExtraOptions returns all the options of the kubelet, before they are
+// split between flags and the KubeletConfiguration.
+func kubeletExtraOptions(k8s bootstrapper.KubernetesConfig, version semver.Version) (map[string]string, error) {
+	if !k8s.ForceExtraOptions {
+		for _, opt := range k8s.ExtraOptions {
+			if opt.Component != Kubelet {
+				continue
+			}
+			warnings, err := util.ValidateExtraOption(opt, &version)
+			if err != nil {
+				return nil, err
+			}
+			for _, w := range warnings {
+				glog.Warningln(w)
+			}
+		}
+	}
+
+	extraOpts, err := ExtraConfigForComponent(Kubelet, k8s.ExtraOptions, version)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating extra configuration for kubelet")
+	}
+
+	extraOpts = SetContainerRuntime(extraOpts, k8s)
+	extraOpts = SetNetworkPlugin(extraOpts, k8s)
+	extraOpts, err = SetClusterDNS(extraOpts, k8s)
+	if err != nil {
+		return nil, err
+	}
+	extraOpts, err = SetNodeReservations(extraOpts, k8s)
+	if err != nil {
+		return nil, err
+	}
+
+	gates, err := util.ParseFeatureGates(k8s.FeatureGates)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing feature gates")
+	}
+	setFeatureGates(extraOpts, Kubelet, gates, version)
+	return extraOpts, nil
+}
+
+// kubeletOptions returns the flags and, for versions that support it, the
+// KubeletConfiguration for the kubelet.  The config is nil for older versions.
+func kubeletOptions(k8s bootstrapper.KubernetesConfig) (map[string]string, map[string]interface{}, error) {
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing kubernetes version")
+	}
+
+	extraOpts, err := kubeletExtraOptions(k8s, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !supportsKubeletConfigFile(version) {
+		return extraOpts, nil, nil
+	}
+
+	flags, config, err := splitKubeletOptions(extraOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	flags["config"] = constants.KubeletConfigFile
+	return flags, config, nil
+}
+
+// NewKubeletConfigFile generates the KubeletConfiguration file for the kubelet.
+// It returns an empty string for versions of the kubelet that are only configured
+// through flags.
+func NewKubeletConfigFile(k8s bootstrapper.KubernetesConfig) (string, error) {
+	_, config, err := kubeletOptions(k8s)
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		return "", nil
+	}
+	return renderKubeletConfig(config)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestNewKubeletConfigFile(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         bootstrapper.KubernetesConfig
+		expectedCfg string
+		flags       []string
+		noFlags     []string
+		shouldErr   bool
+	}{
+		{
+			description: "flags only",
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				FeatureGates:      "HugePages=true",
+			},
+			flags: []string{"--fail-swap-on=false", "--cluster-dns=10.96.0.10", "--feature-gates=HugePages=true"},
+		},
+		{
+			description: "config file",
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				FeatureGates:      "HugePages=true",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "max-pods", Value: "5"},
+					util.ExtraOption{Component: Kubelet, Key: "eviction-hard", Value: "memory.available<100Mi"},
+					util.ExtraOption{Component: Kubelet, Key: "node-labels", Value: "role=dev"},
+				},
+			},
+			expectedCfg: `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+authentication:
+  x509:
+    clientCAFile: /var/lib/localkube/certs/ca.crt
+authorization:
+  mode: Webhook
+cgroupDriver: cgroupfs
+clusterDNS:
+- 10.96.0.10
+clusterDomain: cluster.local
+evictionHard:
+  memory.available: 100Mi
+failSwapOn: false
+featureGates:
+  HugePages: true
+maxPods: 5
+staticPodPath: /etc/kubernetes/manifests
+`,
+			flags:   []string{"--config=/etc/kubernetes/kubelet-config.yaml", "--node-labels=role=dev", "--hostname-override=minikube"},
+			noFlags: []string{"--max-pods", "--fail-swap-on", "--feature-gates"},
+		},
+		{
+			description: "node reservations",
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion:  "v1.10.0",
+				NodeResourcePreset: bootstrapper.NodeResourcePresetSmall,
+			},
+			expectedCfg: `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+authentication:
+  x509:
+    clientCAFile: /var/lib/localkube/certs/ca.crt
+authorization:
+  mode: Webhook
+cgroupDriver: cgroupfs
+clusterDNS:
+- 10.96.0.10
+clusterDomain: cluster.local
+evictionHard:
+  imagefs.available: 15%
+  memory.available: 100Mi
+  nodefs.available: 10%
+  nodefs.inodesFree: 5%
+failSwapOn: false
+kubeReserved:
+  cpu: 50m
+  memory: 64Mi
+staticPodPath: /etc/kubernetes/manifests
+systemReserved:
+  cpu: 50m
+  memory: 64Mi
+`,
+			noFlags: []string{"--kube-reserved", "--system-reserved", "--eviction-hard"},
+		},
+		{
+			description: "invalid value",
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "max-pods", Value: "many"},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "unknown flag",
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.10.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "no-such-flag", Value: "true"},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "unknown flag with force",
+			cfg: bootstrapper.KubernetesConfig{
+				KubernetesVersion: "v1.9.0",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: Kubelet, Key: "no-such-flag", Value: "true"},
+				},
+				ForceExtraOptions: true,
+			},
+			flags: []string{"--no-such-flag=true"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			actualCfg, err := NewKubeletConfigFile(test.cfg)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error generating kubelet config file: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if test.shouldErr {
+				return
+			}
+			if actualCfg != test.expectedCfg {
+				t.Errorf("actual config does not match expected.  actual:\n%sexpected:\n%s", actualCfg, test.expectedCfg)
+			}
+
+			unit, err := NewKubeletConfig(test.cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error generating kubelet unit: %s", err)
+			}
+			for _, f := range test.flags {
+				if !strings.Contains(unit, f) {
+					t.Errorf("Expected kubelet unit to contain %s:\n%s", f, unit)
+				}
+			}
+			for _, f := range test.noFlags {
+				if strings.Contains(unit, f) {
+					t.Errorf("Expected kubelet unit not to contain %s:\n%s", f, unit)
+				}
+			}
+		})
+	}
+}
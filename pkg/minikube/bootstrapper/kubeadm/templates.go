@@ -32,21 +32,28 @@ api:
 kubernetesVersion: {{.KubernetesVersion}}
 certificatesDir: {{.CertDir}}
 networking:
-  serviceSubnet: {{.ServiceCIDR}}
+  serviceSubnet: {{.ServiceCIDR}}{{if .PodSubnet}}
+  podSubnet: {{.PodSubnet}}{{end}}
 etcd:
-  dataDir: {{.EtcdDataDir}}
+  dataDir: {{.EtcdDataDir}}{{if .EtcdExtraArgs}}
+  extraArgs:{{range $i, $val := printMapInOrder .EtcdExtraArgs ": "}}
+    {{$val}}{{end}}{{end}}
 nodeName: {{.NodeName}}
 {{range .ExtraArgs}}{{.Component}}:{{range $i, $val := printMapInOrder .Options ": " }}
   {{$val}}{{end}}
+{{end}}{{range .ExtraVolumes}}{{.Component}}:{{range .Volumes}}
+- name: {{.Name}}
+  hostPath: {{.HostPath}}
+  mountPath: {{.MountPath}}{{end}}
 {{end}}`))
 
 var kubeletSystemdTemplate = template.Must(template.New("kubeletSystemdTemplate").Parse(`
 [Service]
 ExecStart=
-ExecStart=/usr/bin/kubelet {{.ExtraOptions}} {{if .FeatureGates}}--feature-gates={{.FeatureGates}}{{end}}
+ExecStart=/usr/bin/kubelet {{.ExtraOptions}}
 
 [Install]
-{{if or (eq .ContainerRuntime "cri-o") (eq .ContainerRuntime "cri")}}Wants=crio.service{{else}}Wants=docker.socket{{end}}
+{{if or (eq .ContainerRuntime "cri-o") (eq .ContainerRuntime "crio") (eq .ContainerRuntime "cri")}}Wants=crio.service{{else if eq .ContainerRuntime "containerd"}}Wants=containerd.service{{else}}Wants=docker.socket{{end}}
 `))
 
 const kubeletService = `
@@ -168,7 +168,7 @@ func restartKubeProxy(k8s bootstrapper.KubernetesConfig) error {
 		APIServerPort    int
 	}{
 		AdvertiseAddress: k8s.NodeIP,
-		APIServerPort:    util.APIServerPort,
+		APIServerPort:    bootstrapper.GetAPIServerPort(k8s),
 	}
 
 	kubeconfig := bytes.Buffer{}
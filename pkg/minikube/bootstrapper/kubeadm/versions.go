@@ -18,6 +18,7 @@ package kubeadm
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -25,6 +26,8 @@ import (
 	"github.com/blang/semver"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -35,6 +38,7 @@ const (
 	Apiserver         = "apiserver"
 	Scheduler         = "scheduler"
 	ControllerManager = "controller-manager"
+	Etcd              = "etcd"
 )
 
 // ExtraConfigForComponent generates a map of flagname-value pairs for a k8s
@@ -47,6 +51,13 @@ func ExtraConfigForComponent(component string, opts util.ExtraOptionSlice, versi
 
 	for _, opt := range opts {
 		if opt.Component == component {
+			if opt.Remove {
+				if _, ok := versionedOpts[opt.Key]; !ok {
+					glog.Warningf("Unable to remove %s from %s, it isn't set", opt.Key, component)
+				}
+				delete(versionedOpts, opt.Key)
+				continue
+			}
 			if val, ok := versionedOpts[opt.Key]; ok {
 				glog.Infof("Overwriting default %s=%s with user provided %s=%s for component %s", opt.Key, val, opt.Key, opt.Value, component)
 			}
@@ -57,6 +68,16 @@ func ExtraConfigForComponent(component string, opts util.ExtraOptionSlice, versi
 	return versionedOpts, nil
 }
 
+// Components returns the components that can be configured with the kubeadm bootstrapper
+func Components() []string {
+	var components []string
+	for c := range componentToKubeadmConfigKey {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+	return components
+}
+
 type ComponentExtraArgs struct {
 	Component string
 	Options   map[string]string
@@ -68,14 +89,104 @@ var componentToKubeadmConfigKey = map[string]string{
 	Scheduler:         "schedulerExtraArgs",
 	// The Kubelet is not configured in kubeadm, only in systemd.
 	Kubelet: "",
+	// The extra args of etcd are nested in the etcd section of the kubeadm config.
+	Etcd: "",
+}
+
+// HostPathMount is a file or directory on the VM that is mounted into a
+// control plane static pod.
+type HostPathMount struct {
+	Name      string
+	HostPath  string
+	MountPath string
+}
+
+// ComponentExtraVolumes are the extra volumes kubeadm mounts into a static pod
+type ComponentExtraVolumes struct {
+	Component string
+	Volumes   []HostPathMount
+}
+
+var componentToKubeadmVolumesKey = map[string]string{
+	Apiserver:         "apiServerExtraVolumes",
+	ControllerManager: "controllerManagerExtraVolumes",
+	Scheduler:         "schedulerExtraVolumes",
+}
+
+// hasHostPathMount returns whether dir is already mounted at the same path
+func hasHostPathMount(mounts []HostPathMount, dir string) bool {
+	for _, m := range mounts {
+		if m.HostPath == dir && m.MountPath == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// NewComponentExtraVolumes returns the extra volumes for each static pod
+func NewComponentExtraVolumes(k8s bootstrapper.KubernetesConfig) []ComponentExtraVolumes {
+	volumes := map[string][]HostPathMount{}
+	if k8s.AuditPolicy != "" {
+		// The audit log goes to the container log, since kubeadm mounts extra volumes read-only
+		volumes[Apiserver] = append(volumes[Apiserver],
+			HostPathMount{Name: "audit-policy", HostPath: constants.AuditPolicyDir, MountPath: constants.AuditPolicyDir},
+		)
+	}
+	// kubeadm mounts extra volumes as DirectoryOrCreate, so the directory of
+	// each file is mounted, once per component
+	dirs := map[string]int{}
+	for _, f := range k8s.ControlPlaneFiles {
+		dir := path.Dir(f.VMPath)
+		if hasHostPathMount(volumes[f.Component], dir) {
+			continue
+		}
+		volumes[f.Component] = append(volumes[f.Component], HostPathMount{
+			Name:      fmt.Sprintf("control-plane-files-%d", dirs[f.Component]),
+			HostPath:  dir,
+			MountPath: dir,
+		})
+		dirs[f.Component]++
+	}
+
+	keys := []string{}
+	for k := range componentToKubeadmVolumesKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var extraVolumes []ComponentExtraVolumes
+	for _, component := range keys {
+		if len(volumes[component]) > 0 {
+			extraVolumes = append(extraVolumes, ComponentExtraVolumes{
+				Component: componentToKubeadmVolumesKey[component],
+				Volumes:   volumes[component],
+			})
+		}
+	}
+	return extraVolumes
 }
 
-func NewComponentExtraArgs(opts util.ExtraOptionSlice, version semver.Version, featureGates string) ([]ComponentExtraArgs, error) {
+func NewComponentExtraArgs(opts util.ExtraOptionSlice, version semver.Version, featureGates string, force bool) ([]ComponentExtraArgs, error) {
+	gates, err := util.ParseFeatureGates(featureGates)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing feature gates")
+	}
+
 	var kubeadmExtraArgs []ComponentExtraArgs
 	for _, extraOpt := range opts {
 		if _, ok := componentToKubeadmConfigKey[extraOpt.Component]; !ok {
 			return nil, fmt.Errorf("Unknown component %s.  Valid components and kubeadm config are %v", componentToKubeadmConfigKey, componentToKubeadmConfigKey)
 		}
+		if force {
+			continue
+		}
+		warnings, err := util.ValidateExtraOption(extraOpt, &version)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range warnings {
+			glog.Warningln(w)
+		}
 	}
 
 	keys := []string{}
@@ -93,9 +204,7 @@ func NewComponentExtraArgs(opts util.ExtraOptionSlice, version semver.Version, f
 		if err != nil {
 			return nil, errors.Wrapf(err, "getting kubeadm extra args for %s", component)
 		}
-		if featureGates != "" {
-			extraConfig["feature-gates"] = featureGates
-		}
+		setFeatureGates(extraConfig, component, gates, version)
 		if len(extraConfig) > 0 {
 			kubeadmExtraArgs = append(kubeadmExtraArgs, ComponentExtraArgs{
 				Component: kubeadmComponentKey,
@@ -107,6 +216,13 @@ func NewComponentExtraArgs(opts util.ExtraOptionSlice, version semver.Version, f
 	return kubeadmExtraArgs, nil
 }
 
+// setFeatureGates sets the feature gates of a component, if it has any
+func setFeatureGates(opts map[string]string, component string, gates util.FeatureGates, version semver.Version) {
+	if g := gates.ForComponent(component, &version); len(g) > 0 {
+		opts["feature-gates"] = util.FeatureGatesFlag(g)
+	}
+}
+
 func ParseKubernetesVersion(version string) (semver.Version, error) {
 	// Strip leading 'v' prefix from version for semver parsing
 	v, err := semver.Make(version[1:])
@@ -181,7 +297,6 @@ var versionSpecificOpts = []VersionedExtraOption{
 	NewUnversionedOption(Kubelet, "allow-privileged", "true"),
 
 	// Network args
-	NewUnversionedOption(Kubelet, "cluster-dns", "10.96.0.10"),
 	NewUnversionedOption(Kubelet, "cluster-domain", "cluster.local"),
 
 	// Auth args
@@ -24,8 +24,11 @@ import (
 
 	"text/template"
 
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 )
 
 // Kill any running instances.
@@ -45,6 +48,8 @@ Documentation=https://github.com/kubernetes/minikube/tree/master/pkg/localkube
 Type=notify
 Restart=always
 RestartSec=3
+# localkube stops its components one after the other, each with a timeout
+TimeoutStopSec=120
 
 Environment=GODEBUG=netdns=go
 
@@ -145,18 +150,43 @@ func GenLocalkubeStartCmd(kubernetesConfig bootstrapper.KubernetesConfig) (strin
 		flagVals = append(flagVals, "--container-runtime="+kubernetesConfig.ContainerRuntime)
 	}
 
-	if kubernetesConfig.NetworkPlugin != "" {
-		flagVals = append(flagVals, "--network-plugin="+kubernetesConfig.NetworkPlugin)
+	if networkPlugin := bootstrapper.GetNetworkPlugin(kubernetesConfig); networkPlugin != "" {
+		flagVals = append(flagVals, "--network-plugin="+networkPlugin)
 	}
 
-	if kubernetesConfig.FeatureGates != "" {
-		flagVals = append(flagVals, "--feature-gates="+kubernetesConfig.FeatureGates)
+	// Pod CIDRs are allocated to the node by the controller manager and used by the proxy
+	// to tell cluster traffic apart
+	if podCIDR := bootstrapper.GetCNIPodCIDR(kubernetesConfig); podCIDR != "" {
+		flagVals = append(flagVals,
+			"--extra-config=controller-manager.AllocateNodeCIDRs=true",
+			"--extra-config=controller-manager.ClusterCIDR="+podCIDR,
+			"--extra-config=proxy.ClusterCIDR="+podCIDR)
+	}
+
+	gates, err := util.ParseFeatureGates(kubernetesConfig.FeatureGates)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing feature gates")
+	}
+	merged, err := gates.Merged()
+	if err != nil {
+		return "", err
+	}
+	if len(merged) > 0 {
+		flagVals = append(flagVals, "--feature-gates="+util.FeatureGatesFlag(merged))
 	}
 
 	if kubernetesConfig.APIServerName != constants.APIServerName {
 		flagVals = append(flagVals, "--apiserver-name="+kubernetesConfig.APIServerName)
 	}
 
+	if serviceCIDR := bootstrapper.GetServiceCIDR(kubernetesConfig); serviceCIDR != util.DefaultServiceCIDR {
+		flagVals = append(flagVals, "--service-cluster-ip-range="+serviceCIDR)
+	}
+
+	if port := bootstrapper.GetAPIServerPort(kubernetesConfig); port != util.APIServerPort {
+		flagVals = append(flagVals, fmt.Sprintf("--apiserver-port=%d", port))
+	}
+
 	if kubernetesConfig.DNSDomain != "" {
 		flagVals = append(flagVals, "--dns-domain="+kubernetesConfig.DNSDomain)
 	}
@@ -165,7 +195,30 @@ func GenLocalkubeStartCmd(kubernetesConfig bootstrapper.KubernetesConfig) (strin
 		flagVals = append(flagVals, "--node-ip="+kubernetesConfig.NodeIP)
 	}
 
+	// localkube sets apiserver options by their field path in the apiserver config
+	if kubernetesConfig.AuditPolicy != "" {
+		flagVals = append(flagVals,
+			"--extra-config=apiserver.Audit.PolicyFile="+constants.AuditPolicyFile,
+			"--extra-config=apiserver.Audit.LogOptions.Path="+constants.AuditLogFile,
+			"--extra-config=apiserver.Audit.LogOptions.MaxAge="+bootstrapper.AuditLogMaxAge,
+			"--extra-config=apiserver.Audit.LogOptions.MaxBackups="+bootstrapper.AuditLogMaxBackups,
+			"--extra-config=apiserver.Audit.LogOptions.MaxSize="+bootstrapper.AuditLogMaxSize)
+	}
+
 	for _, e := range kubernetesConfig.ExtraOptions {
+		if e.Component == LocalkubeComponent {
+			flag, err := localkubeFlag(e)
+			if err != nil {
+				return "", err
+			}
+			flagVals = append(flagVals, flag)
+			continue
+		}
+		// localkube configures components through structs rather than flags, so there are no defaults to remove
+		if e.Remove {
+			glog.Warningf("Ignoring %s, removing options isn't supported by localkube", e.String())
+			continue
+		}
 		flagVals = append(flagVals, fmt.Sprintf("--extra-config=%s", e.String()))
 	}
 	flags := strings.Join(flagVals, " ")
@@ -185,6 +238,23 @@ func GenLocalkubeStartCmd(kubernetesConfig bootstrapper.KubernetesConfig) (strin
 	return buf.String(), nil
 }
 
+// LocalkubeComponent is the extra-config component of the options of localkube
+// itself, such as localkube.disable-components=proxy
+const LocalkubeComponent = "localkube"
+
+// localkubeFlags are the localkube flags that can be set with extra-config
+var localkubeFlags = []string{"disable-components", "etcd-servers"}
+
+// localkubeFlag returns the localkube flag of a localkube extra option
+func localkubeFlag(e util.ExtraOption) (string, error) {
+	for _, f := range localkubeFlags {
+		if e.Key == f && !e.Remove {
+			return fmt.Sprintf("--%s=%s", e.Key, e.Value), nil
+		}
+	}
+	return "", fmt.Errorf("Unsupported option %s, the localkube options are %v", e.String(), localkubeFlags)
+}
+
 const logsTemplate = "if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; " + `then
   sudo journalctl {{.Flags}} -u localkube
 else
@@ -228,3 +298,14 @@ else
   fi
 fi
 `, constants.LocalkubePIDPath)
+
+// localkubeStopCommand stops localkube and waits for it to exit
+var localkubeStopCommand = fmt.Sprintf("if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; "+`then
+  sudo systemctl stop localkube.service
+else
+  if ps $(cat %[1]s) &>/dev/null; then
+    sudo kill $(cat %[1]s)
+    while ps $(cat %[1]s) &>/dev/null; do sleep 1; done
+  fi
+fi
+`, constants.LocalkubePIDPath)
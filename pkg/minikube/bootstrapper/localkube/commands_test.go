@@ -64,6 +64,66 @@ func TestGetStartCommandExtraOptions(t *testing.T) {
 	}
 }
 
+func TestGetStartCommandLocalkubeOptions(t *testing.T) {
+	k := bootstrapper.KubernetesConfig{
+		ExtraOptions: util.ExtraOptionSlice{
+			util.ExtraOption{Component: LocalkubeComponent, Key: "disable-components", Value: "etcd,proxy"},
+			util.ExtraOption{Component: LocalkubeComponent, Key: "etcd-servers", Value: "https://10.0.0.2:2379"},
+		},
+	}
+	startCommand, err := GetStartCommand(k)
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	for _, arg := range []string{"--disable-components=etcd,proxy", "--etcd-servers=https://10.0.0.2:2379"} {
+		if !strings.Contains(startCommand, arg) {
+			t.Errorf("Expected to find argument: %s. Got: %s", arg, startCommand)
+		}
+	}
+	if strings.Contains(startCommand, "--extra-config=localkube") {
+		t.Errorf("Expected the localkube options not to be passed as extra-config. Got: %s", startCommand)
+	}
+
+	k.ExtraOptions = util.ExtraOptionSlice{util.ExtraOption{Component: LocalkubeComponent, Key: "apiserver-port", Value: "6443"}}
+	if _, err := GetStartCommand(k); err == nil {
+		t.Errorf("Expected an error for an unsupported localkube option")
+	}
+}
+
+func TestGetStartCommandAPIServerPort(t *testing.T) {
+	var tests = []struct {
+		port     int
+		expected bool
+	}{
+		{port: 0},
+		{port: util.APIServerPort},
+		{port: 6443, expected: true},
+	}
+	for _, test := range tests {
+		startCommand, err := GetStartCommand(bootstrapper.KubernetesConfig{APIServerPort: test.port})
+		if err != nil {
+			t.Fatalf("Error generating start command: %s", err)
+		}
+		if found := strings.Contains(startCommand, "--apiserver-port="); found != test.expected {
+			t.Errorf("Port %d: expected --apiserver-port in the start command to be %t. Got: %s", test.port, test.expected, startCommand)
+		}
+	}
+}
+
+func TestGetStartCommandFeatureGates(t *testing.T) {
+	startCommand, err := GetStartCommand(bootstrapper.KubernetesConfig{FeatureGates: "kubelet:DevicePlugins=true,HugePages=true"})
+	if err != nil {
+		t.Fatalf("Error generating start command: %s", err)
+	}
+	if arg := "--feature-gates=DevicePlugins=true,HugePages=true"; !strings.Contains(startCommand, arg) {
+		t.Errorf("Expected to find argument: %s. Got: %s", arg, startCommand)
+	}
+
+	if _, err := GetStartCommand(bootstrapper.KubernetesConfig{FeatureGates: "kubelet:HugePages=true,apiserver:HugePages=false"}); err == nil {
+		t.Errorf("Expected an error for a gate set differently for two components")
+	}
+}
+
 func flagMapToSetFlags(flagMap map[string]string) {
 	for flag, val := range flagMap {
 		gflag.Set(flag, val)
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+// The etcd embedded in localkube serves the grpc gateway on its plain http client URL
+const etcdGatewayURL = "http://localhost:2379/v3alpha"
+
+// etcdGatewayCommand returns the command that posts a request to the etcd grpc gateway
+func etcdGatewayCommand(endpoint, body string) string {
+	return fmt.Sprintf("curl -sSf -X POST -d '%s' %s/%s", body, etcdGatewayURL, endpoint)
+}
+
+// MaintainEtcd runs the maintenance operation through the grpc gateway of the
+// etcd embedded in localkube.
+func (lk *LocalkubeBootstrapper) MaintainEtcd(kubernetesConfig bootstrapper.KubernetesConfig, op string) (string, error) {
+	if err := bootstrapper.ValidateEtcdOperation(op); err != nil {
+		return "", err
+	}
+	if op == bootstrapper.EtcdDefrag {
+		if _, err := lk.cmd.CombinedOutput(etcdGatewayCommand("maintenance/defragment", "{}")); err != nil {
+			return "", errors.Wrap(err, "defragmenting etcd")
+		}
+		return "Finished defragmenting etcd", nil
+	}
+
+	status, err := lk.cmd.CombinedOutput(etcdGatewayCommand("maintenance/status", "{}"))
+	if err != nil {
+		return "", errors.Wrap(err, "getting etcd status")
+	}
+	rev, err := bootstrapper.ParseEtcdRevision(status)
+	if err != nil {
+		return "", errors.Wrap(err, "getting etcd revision")
+	}
+	body := fmt.Sprintf(`{"revision":"%d","physical":true}`, rev)
+	if _, err := lk.cmd.CombinedOutput(etcdGatewayCommand("kv/compaction", body)); err != nil {
+		return "", errors.Wrap(err, "compacting etcd")
+	}
+	return fmt.Sprintf("Compacted etcd at revision %d", rev), nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localkube
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/util"
+)
+
+// localkubeHealthCommand queries the health endpoint of localkube, which only
+// accepts clients with a cert signed by the cluster CA.
+var localkubeHealthCommand = fmt.Sprintf("sudo curl -sS --cacert %[1]sca.crt --cert %[1]sapiserver.crt --key %[1]sapiserver.key https://localhost:%[2]d/status",
+	util.DefaultCertPath, util.LocalkubeHealthPort)
+
+// GetComponentStatus returns the readiness, restarts and last error of the
+// components of localkube.
+func (lk *LocalkubeBootstrapper) GetComponentStatus() ([]bootstrapper.ComponentStatus, error) {
+	out, err := lk.cmd.CombinedOutput(localkubeHealthCommand)
+	if err != nil {
+		return nil, errors.Wrap(err, "querying localkube health endpoint")
+	}
+	// The response is the HealthStatus of pkg/localkube
+	var status struct {
+		Servers []bootstrapper.ComponentStatus `json:"servers"`
+	}
+	if err := json.Unmarshal([]byte(out), &status); err != nil {
+		return nil, errors.Wrapf(err, "parsing localkube health status: %s", out)
+	}
+	return status.Servers, nil
+}
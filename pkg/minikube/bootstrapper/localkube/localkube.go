@@ -19,17 +19,21 @@ package localkube
 import (
 	"fmt"
 	"io"
+	"path"
 	"strings"
 
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/util"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 )
 
@@ -83,6 +87,10 @@ func (lk *LocalkubeBootstrapper) GetClusterLogsTo(follow bool, out io.Writer) er
 
 // GetClusterStatus gets the status of localkube from the host VM.
 func (lk *LocalkubeBootstrapper) GetClusterStatus() (string, error) {
+	// localkube answers on its health endpoint while it runs
+	if _, err := lk.GetComponentStatus(); err == nil {
+		return state.Running.String(), nil
+	}
 	s, err := lk.cmd.CombinedOutput(localkubeStatusCommand)
 	if err != nil {
 		return "", err
@@ -114,10 +122,56 @@ func (lk *LocalkubeBootstrapper) RestartCluster(kubernetesConfig bootstrapper.Ku
 	return lk.StartCluster(kubernetesConfig)
 }
 
+// StopCluster stops localkube, which stops the components it runs, and then
+// the containers of the pods.
+func (lk *LocalkubeBootstrapper) StopCluster(kubernetesConfig bootstrapper.KubernetesConfig) error {
+	cr, err := cruntime.New(cruntime.Config{Type: kubernetesConfig.ContainerRuntime, Runner: lk.cmd})
+	if err != nil {
+		glog.Infof("Not stopping the containers of container runtime %s: %s", kubernetesConfig.ContainerRuntime, err)
+		cr = nil
+	}
+	return bootstrapper.StopKubernetes(lk.cmd, cr, func() error {
+		return lk.cmd.Run(localkubeStopCommand)
+	}, bootstrapper.StopTimeout)
+}
+
+// ResetCluster stops localkube and removes the state of the cluster, including
+// the data of the etcd it embeds.  The certs minikube generates are kept.
+func (lk *LocalkubeBootstrapper) ResetCluster(kubernetesConfig bootstrapper.KubernetesConfig) error {
+	if err := lk.cmd.Run(localkubeStopCommand); err != nil {
+		return errors.Wrap(err, "stopping localkube")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: kubernetesConfig.ContainerRuntime, Runner: lk.cmd})
+	if err != nil {
+		glog.Infof("Not removing the containers of container runtime %s: %s", kubernetesConfig.ContainerRuntime, err)
+		cr = nil
+	}
+	return bootstrapper.ResetClusterState(lk.cmd, cr, []string{path.Join(util.DefaultLocalkubeDirectory, "etcd")})
+}
+
+// ReloadCerts restarts localkube, which reads the certs on startup.
+func (lk *LocalkubeBootstrapper) ReloadCerts(kubernetesConfig bootstrapper.KubernetesConfig) error {
+	return lk.StartCluster(kubernetesConfig)
+}
+
+// UpdateNodeIP signs the apiserver cert for the new IP, if it isn't already, and
+// restarts localkube
+func (lk *LocalkubeBootstrapper) UpdateNodeIP(kubernetesConfig bootstrapper.KubernetesConfig) error {
+	if err := bootstrapper.UpdateAPIServerCert(lk.cmd, kubernetesConfig); err != nil {
+		return errors.Wrap(err, "updating apiserver cert")
+	}
+	return lk.StartCluster(kubernetesConfig)
+}
+
 func (lk *LocalkubeBootstrapper) UpdateCluster(config bootstrapper.KubernetesConfig) error {
 	if config.ShouldLoadCachedImages {
 		// Make best effort to load any cached images
-		go machine.LoadImages(lk.cmd, constants.LocalkubeCachedImages, constants.ImageCacheDir)
+		if cr, err := cruntime.New(cruntime.Config{Type: config.ContainerRuntime, Runner: lk.cmd}); err == nil {
+			images := append(constants.LocalkubeCachedImages, bootstrapper.GetCNICachedImages(config.CNI)...)
+			go machine.LoadImages(lk.cmd, cr, images, constants.ImageCacheDir)
+		} else {
+			glog.Infof("Not loading cached images into container runtime %s: %s", config.ContainerRuntime, err)
+		}
 	}
 
 	copyableFiles := []assets.CopyableFile{}
@@ -137,16 +191,46 @@ func (lk *LocalkubeBootstrapper) UpdateCluster(config bootstrapper.KubernetesCon
 		return errors.Wrap(err, "adding minikube dir assets")
 	}
 	// bundled addons
+	addonData, err := assets.NewAddonTemplateData(config.ServiceCIDR)
+	if err != nil {
+		return errors.Wrap(err, "getting addon template data")
+	}
 	for _, addonBundle := range assets.Addons {
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				copyableFiles = append(copyableFiles, addon)
+			addonFiles, err := addonBundle.Files(addonData)
+			if err != nil {
+				return errors.Wrap(err, "rendering addon")
 			}
+			copyableFiles = append(copyableFiles, addonFiles...)
 		} else if err != nil {
 			return err
 		}
 	}
 
+	cniFiles, err := bootstrapper.GetCNIAssets(config)
+	if err != nil {
+		return errors.Wrap(err, "adding cni files")
+	}
+	copyableFiles = append(copyableFiles, cniFiles...)
+
+	// localkube runs the control plane directly in the VM, so the files only need to be copied
+	controlPlaneFiles, err := bootstrapper.GetControlPlaneFileAssets(config)
+	if err != nil {
+		return errors.Wrap(err, "adding control plane files")
+	}
+	copyableFiles = append(copyableFiles, controlPlaneFiles...)
+
+	auditPolicy, err := bootstrapper.GetAuditPolicyAsset(config)
+	if err != nil {
+		return errors.Wrap(err, "adding audit policy")
+	}
+	if auditPolicy != nil {
+		copyableFiles = append(copyableFiles, auditPolicy)
+	}
+	if err := bootstrapper.PrepareAuditLogDir(lk.cmd, config); err != nil {
+		return err
+	}
+
 	for _, f := range copyableFiles {
 		if err := lk.cmd.Copy(f); err != nil {
 			return err
@@ -17,11 +17,7 @@ limitations under the License.
 package localkube
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -48,7 +44,7 @@ func (l *localkubeCacher) getLocalkubeCacheFilepath() string {
 }
 
 func (l *localkubeCacher) getLocalkubeSha256CacheFilepath() string {
-	return l.getLocalkubeCacheFilepath() + ".sha256"
+	return util.ChecksumFilepath(l.getLocalkubeCacheFilepath())
 }
 
 func localkubeURIWasSpecified(config bootstrapper.KubernetesConfig) bool {
@@ -57,43 +53,28 @@ func localkubeURIWasSpecified(config bootstrapper.KubernetesConfig) bool {
 }
 
 func (l *localkubeCacher) isLocalkubeCached() bool {
+	if _, err := os.Stat(l.getLocalkubeCacheFilepath()); os.IsNotExist(err) {
+		return false
+	}
+
 	url, err := util.GetLocalkubeDownloadURL(l.k8sConf.KubernetesVersion, constants.LocalkubeLinuxFilename)
 	if err != nil {
-		glog.Warningf("Unable to get localkube checksum url...continuing.")
-		return true
+		glog.Warningf("Unable to get localkube checksum url...verifying against cached checksum.")
+		return l.verifyCachedLocalkube()
 	}
 	opts := download.FileOptions{
 		Mkdirs: download.MkdirAll,
 	}
 
 	if err := download.ToFile(url+".sha256", l.getLocalkubeSha256CacheFilepath(), opts); err != nil {
-		glog.Warningf("Unable to check localkube checksum... continuing.")
-		return true
-	}
-
-	if _, err := os.Stat(l.getLocalkubeCacheFilepath()); os.IsNotExist(err) {
-		return false
-	}
-
-	localkubeSha256, err := ioutil.ReadFile(l.getLocalkubeSha256CacheFilepath())
-	if err != nil {
-		glog.Infof("Error reading localkube checksum: %s", err)
-		return false
-	}
-
-	h := sha256.New()
-	f, err := os.Open(l.getLocalkubeCacheFilepath())
-	if err != nil {
-		glog.Infof("Error opening localkube for checksum verification: %s", err)
-		return false
-	}
-	if _, err := io.Copy(h, f); err != nil {
-		glog.Infof("Error copying contents to hasher: %s", err)
+		glog.Warningf("Unable to download localkube checksum...verifying against cached checksum.")
 	}
+	return l.verifyCachedLocalkube()
+}
 
-	actualChecksum := hex.EncodeToString(h.Sum(nil))
-	if strings.TrimSpace(string(localkubeSha256)) != actualChecksum {
-		glog.Infof("Localkube checksums do not match actual: %s expected: %s", actualChecksum, string(localkubeSha256))
+func (l *localkubeCacher) verifyCachedLocalkube() bool {
+	if err := util.VerifyChecksumFile(l.getLocalkubeCacheFilepath()); err != nil {
+		glog.Infof("Localkube failed checksum verification: %s", err)
 		return false
 	}
 	return true
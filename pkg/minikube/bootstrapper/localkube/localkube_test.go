@@ -18,6 +18,7 @@ package localkube
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
@@ -169,6 +170,35 @@ func TestGetLocalkubeStatus(t *testing.T) {
 	}
 }
 
+func TestGetComponentStatus(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		localkubeHealthCommand: `{"ready":false,"servers":[{"name":"etcd","ready":true,"restarts":0},` +
+			`{"name":"scheduler","ready":false,"restarts":2,"lastError":"bind: address already in use"}]}`,
+	})
+	l := LocalkubeBootstrapper{f}
+	components, err := l.GetComponentStatus()
+	if err != nil {
+		t.Fatalf("Error getting component status: %s", err)
+	}
+	expected := []bootstrapper.ComponentStatus{
+		{Name: "etcd", Ready: true},
+		{Name: "scheduler", Restarts: 2, LastError: "bind: address already in use"},
+	}
+	if !reflect.DeepEqual(components, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, components)
+	}
+
+	// The cluster runs as long as localkube answers
+	status, err := l.GetClusterStatus()
+	if err != nil {
+		t.Fatalf("Error getting cluster status: %s", err)
+	}
+	if status != "Running" {
+		t.Errorf("Expected status Running, got %s", status)
+	}
+}
+
 func TestGetHostLogs(t *testing.T) {
 	logs, err := GetLogsCommand(false)
 	if err != nil {
@@ -220,3 +250,22 @@ func TestGetHostLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestMaintainEtcd(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		etcdGatewayCommand("maintenance/status", "{}"):                           `{"header":{"revision":"42"},"version":"3.1.10"}`,
+		etcdGatewayCommand("kv/compaction", `{"revision":"42","physical":true}`): `{"header":{"revision":"42"}}`,
+	})
+	l := LocalkubeBootstrapper{f}
+	out, err := l.MaintainEtcd(bootstrapper.KubernetesConfig{}, bootstrapper.EtcdCompact)
+	if err != nil {
+		t.Fatalf("Error compacting etcd: %s", err)
+	}
+	if out != "Compacted etcd at revision 42" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if _, err := l.MaintainEtcd(bootstrapper.KubernetesConfig{}, bootstrapper.EtcdDefrag); err == nil {
+		t.Errorf("Expected an error when the defragment request fails")
+	}
+}
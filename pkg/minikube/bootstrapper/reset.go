@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// KubeletDataDir is where the kubelet keeps the volumes and state of its pods
+const KubeletDataDir = "/var/lib/kubelet"
+
+// ClusterStateDirs hold the state of the kubelet and the CNI plugins. Their
+// contents are removed when the cluster is reset.
+var ClusterStateDirs = []string{KubeletDataDir, constants.CNIConfDir, "/var/lib/cni"}
+
+// ResetClusterState removes the containers of the cluster, the contents of
+// ClusterStateDirs and the data dirs, which hold the etcd data of the cluster.
+// Whatever runs the cluster must already be stopped. The runtime may be nil if
+// minikube doesn't manage it.
+func ResetClusterState(runner CommandRunner, cr cruntime.Manager, dataDirs []string) error {
+	if cr != nil {
+		ids, err := cr.ListContainers("")
+		if err != nil {
+			return errors.Wrap(err, "listing containers")
+		}
+		if err := cr.KillContainers(ids); err != nil {
+			return errors.Wrap(err, "removing containers")
+		}
+	}
+
+	// The volumes of the pods are still mounted below the kubelet dir
+	unmount := fmt.Sprintf(`sudo sh -c "grep ' %s/' /proc/mounts | cut -d' ' -f2 | sort -r | xargs -r umount"`, KubeletDataDir)
+	if err := runner.Run(unmount); err != nil {
+		return errors.Wrap(err, "unmounting pod volumes")
+	}
+
+	var paths []string
+	for _, d := range ClusterStateDirs {
+		paths = append(paths, d+"/*")
+	}
+	paths = append(paths, dataDirs...)
+	if err := runner.Run(fmt.Sprintf(`sudo sh -c "rm -rf %s"`, strings.Join(paths, " "))); err != nil {
+		return errors.Wrap(err, "removing cluster state")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func TestResetClusterState(t *testing.T) {
+	unmount := `sudo sh -c "grep ' /var/lib/kubelet/' /proc/mounts | cut -d' ' -f2 | sort -r | xargs -r umount"`
+	remove := `sudo sh -c "rm -rf /var/lib/kubelet/* /etc/cni/net.d/* /var/lib/cni/* /var/lib/etcd"`
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		`docker ps -a --filter="name=k8s_" --format="{{.ID}}"`: "abc\ndef\n",
+		"docker rm -f abc def":                                 "",
+		unmount:                                                "",
+		remove:                                                 "",
+	})
+	r := &recordingRunner{FakeCommandRunner: f}
+	cr, err := cruntime.New(cruntime.Config{Type: "docker", Runner: r})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := ResetClusterState(r, cr, []string{"/var/lib/etcd"}); err != nil {
+		t.Fatalf("Error resetting cluster state: %s", err)
+	}
+	expected := []string{"docker rm -f abc def", unmount, remove}
+	if !reflect.DeepEqual(r.cmds, expected) {
+		t.Errorf("Expected commands %v, got %v", expected, r.cmds)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+)
+
+// The presets of the resources the kubelet reserves for the system and the
+// Kubernetes daemons, which are subtracted from the allocatable resources of the node.
+const (
+	// NodeResourcePresetNone reserves nothing, which is the kubelet default
+	NodeResourcePresetNone = "none"
+	// NodeResourcePresetSmall reserves a fixed minimum, for VMs with little to spare
+	NodeResourcePresetSmall = "small"
+	// NodeResourcePresetBalanced reserves a share of the memory and CPUs of the node
+	NodeResourcePresetBalanced = "balanced"
+)
+
+// NodeResourcePresets are the presets that can be passed to --node-resource-preset
+var NodeResourcePresets = []string{NodeResourcePresetNone, NodeResourcePresetSmall, NodeResourcePresetBalanced}
+
+// The hard eviction thresholds of the kubelet other than memory.available.
+// Setting --eviction-hard replaces all of the kubelet defaults, so they are repeated here.
+const defaultDiskEvictionThresholds = "nodefs.available<10%,nodefs.inodesFree<5%,imagefs.available<15%"
+
+// ValidateNodeResourcePreset returns an error if the preset isn't one minikube knows
+func ValidateNodeResourcePreset(name string) error {
+	for _, p := range NodeResourcePresets {
+		if p == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown node resource preset %s. Valid presets are: %v", name, NodeResourcePresets)
+}
+
+// GetNodeReservations returns the kubelet flags that reserve resources for the
+// preset of the config.  Profiles saved before the presets existed reserve
+// nothing.  The balanced preset falls back to the small one when the resources
+// of the node are unknown.
+func GetNodeReservations(k8s KubernetesConfig) (map[string]string, error) {
+	preset := k8s.NodeResourcePreset
+	if preset == "" {
+		preset = NodeResourcePresetNone
+	}
+	if err := ValidateNodeResourcePreset(preset); err != nil {
+		return nil, err
+	}
+	if preset == NodeResourcePresetBalanced && (k8s.NodeMemory <= 0 || k8s.NodeCPUs <= 0) {
+		preset = NodeResourcePresetSmall
+	}
+
+	var cpu, memory, eviction int
+	switch preset {
+	case NodeResourcePresetNone:
+		return map[string]string{}, nil
+	case NodeResourcePresetSmall:
+		cpu, memory, eviction = 50, 64, 100
+	case NodeResourcePresetBalanced:
+		// 5% of the node each for the system and Kubernetes, and 5% of the
+		// memory as the eviction threshold
+		cpu = maxInt(100, k8s.NodeCPUs*1000/20)
+		memory = maxInt(128, k8s.NodeMemory/20)
+		eviction = maxInt(100, k8s.NodeMemory/20)
+	}
+	reserved := fmt.Sprintf("cpu=%dm,memory=%dMi", cpu, memory)
+	return map[string]string{
+		"kube-reserved":   reserved,
+		"system-reserved": reserved,
+		"eviction-hard":   fmt.Sprintf("memory.available<%dMi,%s", eviction, defaultDiskEvictionThresholds),
+	}, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
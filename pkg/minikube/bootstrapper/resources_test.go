@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetNodeReservations(t *testing.T) {
+	var tests = []struct {
+		description string
+		cfg         KubernetesConfig
+		expected    map[string]string
+		shouldErr   bool
+	}{
+		{
+			description: "no preset",
+			cfg:         KubernetesConfig{NodeMemory: 2048, NodeCPUs: 2},
+			expected:    map[string]string{},
+		},
+		{
+			description: "small",
+			cfg:         KubernetesConfig{NodeResourcePreset: NodeResourcePresetSmall, NodeMemory: 8192, NodeCPUs: 4},
+			expected: map[string]string{
+				"kube-reserved":   "cpu=50m,memory=64Mi",
+				"system-reserved": "cpu=50m,memory=64Mi",
+				"eviction-hard":   "memory.available<100Mi,nodefs.available<10%,nodefs.inodesFree<5%,imagefs.available<15%",
+			},
+		},
+		{
+			description: "balanced on a small vm",
+			cfg:         KubernetesConfig{NodeResourcePreset: NodeResourcePresetBalanced, NodeMemory: 2048, NodeCPUs: 2},
+			expected: map[string]string{
+				"kube-reserved":   "cpu=100m,memory=128Mi",
+				"system-reserved": "cpu=100m,memory=128Mi",
+				"eviction-hard":   "memory.available<102Mi,nodefs.available<10%,nodefs.inodesFree<5%,imagefs.available<15%",
+			},
+		},
+		{
+			description: "balanced on a large vm",
+			cfg:         KubernetesConfig{NodeResourcePreset: NodeResourcePresetBalanced, NodeMemory: 16384, NodeCPUs: 8},
+			expected: map[string]string{
+				"kube-reserved":   "cpu=400m,memory=819Mi",
+				"system-reserved": "cpu=400m,memory=819Mi",
+				"eviction-hard":   "memory.available<819Mi,nodefs.available<10%,nodefs.inodesFree<5%,imagefs.available<15%",
+			},
+		},
+		{
+			description: "balanced without node resources",
+			cfg:         KubernetesConfig{NodeResourcePreset: NodeResourcePresetBalanced},
+			expected: map[string]string{
+				"kube-reserved":   "cpu=50m,memory=64Mi",
+				"system-reserved": "cpu=50m,memory=64Mi",
+				"eviction-hard":   "memory.available<100Mi,nodefs.available<10%,nodefs.inodesFree<5%,imagefs.available<15%",
+			},
+		},
+		{
+			description: "unknown preset",
+			cfg:         KubernetesConfig{NodeResourcePreset: "large"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			actual, err := GetNodeReservations(test.cfg)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected an error, got %v", actual)
+			}
+			if !test.shouldErr && !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// StopTimeout is how long StopKubernetes waits for Kubernetes to shut down
+const StopTimeout = 2 * time.Minute
+
+// The containers of the control plane are stopped after those of the other
+// pods, in this order, so that etcd is stopped last once nothing writes to it.
+var controlPlaneStopOrder = []string{"kube-controller-manager", "kube-scheduler", "kube-apiserver", "etcd"}
+
+// StopKubernetes shuts Kubernetes down before the machine is powered off.
+// stopServices stops whatever would restart the containers, such as the kubelet.
+// The containers of the pods are then stopped gracefully, followed by the
+// control plane and etcd, and the filesystem is flushed.  The runtime may be
+// nil if minikube doesn't manage it.  It gives up after the timeout.
+func StopKubernetes(runner CommandRunner, cr cruntime.Manager, stopServices func() error, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- stopKubernetes(runner, cr, stopServices)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s stopping Kubernetes", timeout)
+	}
+}
+
+func stopKubernetes(runner CommandRunner, cr cruntime.Manager, stopServices func() error) error {
+	if err := stopServices(); err != nil {
+		return errors.Wrap(err, "stopping services")
+	}
+
+	if cr != nil {
+		all, err := cr.ListContainers("")
+		if err != nil {
+			return errors.Wrap(err, "listing containers")
+		}
+		controlPlane := map[string][]string{}
+		isControlPlane := map[string]bool{}
+		for _, name := range controlPlaneStopOrder {
+			ids, err := cr.ListContainers(name)
+			if err != nil {
+				return errors.Wrapf(err, "listing %s containers", name)
+			}
+			controlPlane[name] = ids
+			for _, id := range ids {
+				isControlPlane[id] = true
+			}
+		}
+
+		var pods []string
+		for _, id := range all {
+			if !isControlPlane[id] {
+				pods = append(pods, id)
+			}
+		}
+		if err := cr.StopContainers(pods); err != nil {
+			return errors.Wrap(err, "stopping containers")
+		}
+		for _, name := range controlPlaneStopOrder {
+			if err := cr.StopContainers(controlPlane[name]); err != nil {
+				return errors.Wrapf(err, "stopping %s", name)
+			}
+		}
+	}
+
+	// etcd writes its data on shutdown, make sure it reaches the disk
+	glog.Infoln("Flushing the filesystem")
+	if err := runner.Run("sync"); err != nil {
+		return errors.Wrap(err, "flushing the filesystem")
+	}
+	return nil
+}
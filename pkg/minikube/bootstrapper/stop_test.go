@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// recordingRunner records the commands it runs
+type recordingRunner struct {
+	*FakeCommandRunner
+	cmds []string
+}
+
+func (r *recordingRunner) Run(cmd string) error {
+	r.cmds = append(r.cmds, cmd)
+	return r.FakeCommandRunner.Run(cmd)
+}
+
+func TestStopKubernetes(t *testing.T) {
+	list := func(name string) string {
+		return fmt.Sprintf(`docker ps -a --filter="name=k8s_%s" --format="{{.ID}}"`, name)
+	}
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		list(""):                        "etcd1\napi1\npod1\npod2\n",
+		list("kube-controller-manager"): "",
+		list("kube-scheduler"):          "",
+		list("kube-apiserver"):          "api1\n",
+		list("etcd"):                    "etcd1\n",
+		"docker stop pod1 pod2":         "",
+		"docker stop api1":              "",
+		"docker stop etcd1":             "",
+		"sync":                          "",
+	})
+	r := &recordingRunner{FakeCommandRunner: f}
+	cr, err := cruntime.New(cruntime.Config{Type: "docker", Runner: r})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	stopped := false
+	if err := StopKubernetes(r, cr, func() error { stopped = true; return nil }, time.Minute); err != nil {
+		t.Fatalf("Error stopping Kubernetes: %s", err)
+	}
+	if !stopped {
+		t.Errorf("Expected the services to be stopped")
+	}
+	expected := []string{"docker stop pod1 pod2", "docker stop api1", "docker stop etcd1", "sync"}
+	if !reflect.DeepEqual(r.cmds, expected) {
+		t.Errorf("Expected commands %v, got %v", expected, r.cmds)
+	}
+}
+
+func TestStopKubernetesTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	err := StopKubernetes(NewFakeCommandRunner(), nil, func() error { <-block; return nil }, 10*time.Millisecond)
+	if err == nil {
+		t.Errorf("Expected a timeout error")
+	}
+}
@@ -171,9 +171,13 @@ func GetHostDriverIP(api libmachine.API) (net.IP, error) {
 }
 
 func engineOptions(config MachineConfig) *engine.Options {
+	serviceCIDR := config.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = pkgutil.DefaultServiceCIDR
+	}
 	o := engine.Options{
 		Env:              config.DockerEnv,
-		InsecureRegistry: append([]string{pkgutil.DefaultServiceCIDR}, config.InsecureRegistry...),
+		InsecureRegistry: append([]string{serviceCIDR}, config.InsecureRegistry...),
 		RegistryMirror:   config.RegistryMirror,
 		ArbitraryFlags:   config.DockerOpt,
 	}
@@ -96,5 +96,6 @@ func createNoneHost(config MachineConfig) *none.Driver {
 			MachineName: cfg.GetMachineName(),
 			StorePath:   constants.GetMinipath(),
 		},
+		ContainerRuntime: config.ContainerRuntime,
 	}
 }
@@ -41,6 +41,8 @@ type MachineConfig struct {
 	NFSShare            []string
 	NFSSharesRoot       string
 	UUID                string // Only used by hyperkit to restore the mac address
+	ContainerRuntime    string
+	ServiceCIDR         string // Added to the insecure registries of the docker daemon
 }
 
 // Config contains machine and k8s config
@@ -93,7 +93,11 @@ const (
 	MinimumDiskSizeMB   = 2000
 	DefaultVMDriver     = "virtualbox"
 	DefaultStatusFormat = "minikube: {{.MinikubeStatus}}\n" +
-		"cluster: {{.ClusterStatus}}\n" + "kubectl: {{.KubeconfigStatus}}\n"
+		"cluster: {{.ClusterStatus}}\n" +
+		"{{if .ContainerRuntime}}{{.ContainerRuntime}}: {{.ContainerRuntimeStatus}}\n{{end}}" +
+		"{{range .Components}}  {{.Name}}: {{if .Ready}}Ready{{else}}Not Ready{{end}}" +
+		"{{if .Restarts}}, restarted {{.Restarts}} times{{end}}{{if .LastError}}, last error: {{.LastError}}{{end}}\n{{end}}" +
+		"kubectl: {{.KubeconfigStatus}}\n"
 	DefaultAddonListFormat     = "- {{.AddonName}}: {{.AddonStatus}}\n"
 	DefaultConfigViewFormat    = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
 	DefaultCacheListFormat     = "{{.CacheImage}}\n"
@@ -114,7 +118,12 @@ var ConfigFile = MakeMiniPath("config", "config.json")
 
 // GetProfileFile returns the Minikube profile config file
 func GetProfileFile(profile string) string {
-	return filepath.Join(GetMinipath(), "profiles", profile, "config.json")
+	return filepath.Join(GetProfilePath(profile), "config.json")
+}
+
+// GetProfilePath returns the directory with the config and certificates of a profile
+func GetProfilePath(profile string) string {
+	return filepath.Join(GetMinipath(), "profiles", profile)
 }
 
 var LocalkubeDownloadURLPrefix = "https://storage.googleapis.com/minikube/k8sReleases/"
@@ -128,6 +137,13 @@ const ReportingURL = "https://clouderrorreporting.googleapis.com/v1beta1/project
 const AddonsPath = "/etc/kubernetes/addons"
 const FilesPath = "/files"
 
+const (
+	// DefaultPodCIDR is the pod network used when a CNI is selected with --cni
+	DefaultPodCIDR = "10.244.0.0/16"
+	CNIConfDir     = "/etc/cni/net.d"
+	CNIBinDir      = "/opt/cni/bin"
+)
+
 const (
 	RemoteLocalKubeErrPath = "/var/lib/localkube/localkube.err"
 	RemoteLocalKubeOutPath = "/var/lib/localkube/localkube.out"
@@ -138,6 +154,33 @@ const (
 	KubeletServiceFile     = "/lib/systemd/system/kubelet.service"
 	KubeletSystemdConfFile = "/etc/systemd/system/kubelet.service.d/10-kubeadm.conf"
 	KubeadmConfigFile      = "/var/lib/kubeadm.yaml"
+	KubeletConfigFile      = "/etc/kubernetes/kubelet-config.yaml"
+)
+
+const (
+	// KubeadmEtcdDataDir is where the etcd of kubeadm clusters keeps its data.
+	// /var/lib/localkube is on the persistent disk of the minikube VM.
+	KubeadmEtcdDataDir = "/var/lib/localkube/kubeadm/etcd"
+	// LegacyKubeadmEtcdDataDir is where older minikube versions kept the etcd data
+	// of kubeadm clusters, which is lost when the VM reboots.
+	LegacyKubeadmEtcdDataDir = "/data"
+)
+
+// KubeadmKubeconfigFiles are the kubeconfigs kubeadm generates with credentials signed by the CA
+var KubeadmKubeconfigFiles = []string{
+	"/etc/kubernetes/admin.conf",
+	"/etc/kubernetes/kubelet.conf",
+	"/etc/kubernetes/controller-manager.conf",
+	"/etc/kubernetes/scheduler.conf",
+}
+
+const (
+	// AuditPolicyDir holds the audit policy passed with --audit-policy
+	AuditPolicyDir  = "/etc/kubernetes/audit"
+	AuditPolicyFile = AuditPolicyDir + "/policy.yaml"
+	// AuditLogDir is where the apiserver writes its audit log
+	AuditLogDir  = "/var/log/kubernetes/audit"
+	AuditLogFile = AuditLogDir + "/audit.log"
 )
 
 const (
@@ -158,8 +201,8 @@ func GetKubernetesReleaseURL(binaryName, version string) string {
 	return fmt.Sprintf("https://storage.googleapis.com/kubernetes-release/release/%s/bin/linux/amd64/%s", version, binaryName)
 }
 
-func GetKubernetesReleaseURLSha1(binaryName, version string) string {
-	return fmt.Sprintf("%s.sha1", GetKubernetesReleaseURL(binaryName, version))
+func GetKubernetesReleaseURLSha256(binaryName, version string) string {
+	return GetKubernetesReleaseURL(binaryName, version) + ShaSuffix
 }
 
 const IsMinikubeChildProcess = "IS_MINIKUBE_CHILD_PROCESS"
@@ -182,7 +225,7 @@ var LocalkubeCachedImages = []string{
 	"k8s.gcr.io/pause-amd64:3.0",
 
 	//Storage Provisioner
-	"gcr.io/k8s-minikube/storage-provisioner:v1.8.0",
+	"gcr.io/k8s-minikube/storage-provisioner:v1.8.2",
 }
 
 func GetKubeadmCachedImages(version string) []string {
@@ -210,7 +253,7 @@ func GetKubeadmCachedImages(version string) []string {
 		"k8s.gcr.io/kube-apiserver-amd64:" + version,
 
 		//Storage Provisioner
-		"gcr.io/k8s-minikube/storage-provisioner:v1.8.0",
+		"gcr.io/k8s-minikube/storage-provisioner:v1.8.2",
 	}
 }
 
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"path"
+	"text/template"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// ContainerdConfigFile is where the containerd configuration is written in the VM
+const ContainerdConfigFile = "/etc/containerd/config.toml"
+
+// containerdNamespace is the containerd namespace the CRI plugin keeps Kubernetes images in
+const containerdNamespace = "k8s.io"
+
+var containerdConfigTemplate = template.Must(template.New("containerdConfigTemplate").Parse(`root = "/var/lib/containerd"
+state = "/run/containerd"
+oom_score = 0
+
+[grpc]
+  address = "{{.Socket}}"
+
+[plugins]
+  [plugins.cri]
+    stream_server_port = "10010"
+    [plugins.cri.containerd]
+      snapshotter = "overlayfs"
+    [plugins.cri.cni]
+      bin_dir = "/opt/cni/bin"
+      conf_dir = "/etc/cni/net.d"
+    [plugins.cri.registry.mirrors]
+      [plugins.cri.registry.mirrors."docker.io"]
+        endpoint = [{{range .RegistryMirrors}}"{{.}}", {{end}}"https://registry-1.docker.io"]{{range .InsecureRegistries}}
+      [plugins.cri.registry.mirrors."{{.}}"]
+        endpoint = ["http://{{.}}"]{{end}}
+`))
+
+// Containerd contains containerd runtime state
+type Containerd struct {
+	Socket             string
+	Runner             CommandRunner
+	RegistryMirrors    []string
+	InsecureRegistries []string
+}
+
+// Name is a human readable name for containerd
+func (r *Containerd) Name() string {
+	return "containerd"
+}
+
+// SocketPath returns the path to the socket file for containerd
+func (r *Containerd) SocketPath() string {
+	if r.Socket != "" {
+		return r.Socket
+	}
+	return "/run/containerd/containerd.sock"
+}
+
+// Available returns an error if it is not possible to use this runtime on a host
+func (r *Containerd) Available() error {
+	// The minikube ISO only ships the docker-containerd binaries of docker
+	if err := r.Runner.Run("command -v containerd"); err != nil {
+		return errors.Wrap(err, "containerd is not installed, the minikube ISO doesn't include it")
+	}
+	return nil
+}
+
+// Active returns if containerd is active on the host
+func (r *Containerd) Active() bool {
+	return isServiceActive(r.Runner, "containerd")
+}
+
+// Enable idempotently enables containerd on a host. The config is rewritten
+// every time, and containerd is only restarted if it changed.
+func (r *Containerd) Enable() error {
+	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
+		return err
+	}
+	// pass through --registry-mirror and --insecure-registry to containerd
+	c, err := GenerateContainerdConfig(r.RegistryMirrors, r.InsecureRegistries)
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(c))
+	newFile := ContainerdConfigFile + ".new"
+	if err := r.Runner.Run(fmt.Sprintf("sudo mkdir -p %s && printf %%s %s | base64 -d | sudo tee %s", path.Dir(ContainerdConfigFile), encoded, newFile)); err != nil {
+		return errors.Wrap(err, "writing containerd config")
+	}
+	if err := r.Runner.Run(fmt.Sprintf("sudo cmp -s %s %s", newFile, ContainerdConfigFile)); err == nil {
+		if err := r.Runner.Run(fmt.Sprintf("sudo rm -f %s", newFile)); err != nil {
+			return errors.Wrap(err, "removing containerd config")
+		}
+		return r.Runner.Run("sudo systemctl start containerd")
+	}
+	if err := r.Runner.Run(fmt.Sprintf("sudo mv %s %s", newFile, ContainerdConfigFile)); err != nil {
+		return errors.Wrap(err, "replacing containerd config")
+	}
+	return r.Runner.Run("sudo systemctl restart containerd")
+}
+
+// Disable idempotently disables containerd on a host
+func (r *Containerd) Disable() error {
+	return r.Runner.Run("sudo systemctl stop containerd")
+}
+
+// KubeletOptions returns kubelet options for containerd
+func (r *Containerd) KubeletOptions() map[string]string {
+	return criKubeletOptions(r.SocketPath())
+}
+
+// LoadImage loads an image into this runtime
+func (r *Containerd) LoadImage(path string) error {
+	glog.Infof("Loading image: %s", path)
+	if err := r.Runner.Run(fmt.Sprintf("sudo ctr --namespace=%s images import %s", containerdNamespace, path)); err != nil {
+		return errors.Wrapf(err, "ctr images import %s", path)
+	}
+	return nil
+}
+
+// ListContainers returns a list of Kubernetes containers
+func (r *Containerd) ListContainers(filter string) ([]string, error) {
+	return listCRIContainers(r.Runner, filter)
+}
+
+// KillContainers removes containers based on ID
+func (r *Containerd) KillContainers(ids []string) error {
+	return killCRIContainers(r.Runner, ids)
+}
+
+// StopContainers stops containers based on ID
+func (r *Containerd) StopContainers(ids []string) error {
+	return stopCRIContainers(r.Runner, ids)
+}
+
+// ContainerLogs returns the command to retrieve the log for a container based on ID
+func (r *Containerd) ContainerLogs(id string, len int, follow bool) string {
+	return criContainerLogs(id, len, follow)
+}
+
+// ContainerExec returns the command to run a command in a container based on ID
+func (r *Containerd) ContainerExec(id string, command string) string {
+	return criContainerExec(id, command)
+}
+
+// GenerateContainerdConfig returns a containerd config.toml that pulls docker.io
+// images through the registry mirrors and talks plain http to the insecure registries.
+// Insecure registries given as CIDRs can't be expressed in the containerd config
+// and are skipped.
+func GenerateContainerdConfig(registryMirrors []string, insecureRegistries []string) (string, error) {
+	opts := struct {
+		Socket             string
+		RegistryMirrors    []string
+		InsecureRegistries []string
+	}{
+		Socket:          (&Containerd{}).SocketPath(),
+		RegistryMirrors: registryMirrors,
+	}
+	for _, r := range insecureRegistries {
+		if _, _, err := net.ParseCIDR(r); err == nil {
+			glog.Infof("Skipping insecure registry CIDR %s in containerd config", r)
+			continue
+		}
+		opts.InsecureRegistries = append(opts.InsecureRegistries, r)
+	}
+
+	var b bytes.Buffer
+	if err := containerdConfigTemplate.Execute(&b, opts); err != nil {
+		return "", errors.Wrap(err, "executing containerd config template")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// These helpers are shared by the runtimes that implement the CRI, and are
+// managed through crictl.
+
+const crictlConfigFile = "/etc/crictl.yaml"
+
+// populateCRIConfig points crictl at the runtime's socket
+func populateCRIConfig(cr CommandRunner, socket string) error {
+	c := fmt.Sprintf("runtime-endpoint: unix://%s\n", socket)
+	cmd := fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | sudo tee %s", path.Dir(crictlConfigFile), c, crictlConfigFile)
+	if err := cr.Run(cmd); err != nil {
+		return errors.Wrap(err, "populating crictl config")
+	}
+	return nil
+}
+
+// criKubeletOptions returns the kubelet flags for a remote CRI runtime
+func criKubeletOptions(socket string) map[string]string {
+	return map[string]string{
+		"container-runtime":          "remote",
+		"container-runtime-endpoint": socket,
+		"image-service-endpoint":     socket,
+		"runtime-request-timeout":    "15m",
+	}
+}
+
+// listCRIContainers returns a list of containers using crictl
+func listCRIContainers(cr CommandRunner, filter string) ([]string, error) {
+	content, err := cr.CombinedOutput(fmt.Sprintf(`sudo crictl ps -a --quiet --name=%s`, filter))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing containers")
+	}
+	return splitIDs(content), nil
+}
+
+// killCRIContainers kills a list of containers using crictl
+func killCRIContainers(cr CommandRunner, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Killing containers: %s", ids)
+	return cr.Run(fmt.Sprintf("sudo crictl rm -f %s", strings.Join(ids, " ")))
+}
+
+// stopCRIContainers stops containers using crictl
+func stopCRIContainers(cr CommandRunner, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Stopping containers: %s", ids)
+	return cr.Run(fmt.Sprintf("sudo crictl stop %s", strings.Join(ids, " ")))
+}
+
+// criContainerLogs returns the command to retrieve the log for a container based on ID
+func criContainerLogs(id string, len int, follow bool) string {
+	var cmd []string
+	cmd = append(cmd, "sudo crictl logs")
+	if len > 0 {
+		cmd = append(cmd, fmt.Sprintf("--tail %d", len))
+	}
+	if follow {
+		cmd = append(cmd, "--follow")
+	}
+	cmd = append(cmd, id)
+	return strings.Join(cmd, " ")
+}
+
+// criContainerExec returns the command to run a command in a container based on ID
+func criContainerExec(id string, command string) string {
+	return fmt.Sprintf("sudo crictl exec %s %s", id, command)
+}
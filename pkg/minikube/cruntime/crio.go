@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// CRIO contains CRIO runtime state
+type CRIO struct {
+	Socket string
+	Runner CommandRunner
+}
+
+// Name is a human readable name for CRIO
+func (r *CRIO) Name() string {
+	return "CRI-O"
+}
+
+// SocketPath returns the path to the socket file for CRIO
+func (r *CRIO) SocketPath() string {
+	if r.Socket != "" {
+		return r.Socket
+	}
+	return "/var/run/crio/crio.sock"
+}
+
+// Available returns an error if it is not possible to use this runtime on a host
+func (r *CRIO) Available() error {
+	return r.Runner.Run("command -v crio")
+}
+
+// Active returns if CRIO is active on the host
+func (r *CRIO) Active() bool {
+	return isServiceActive(r.Runner, "crio")
+}
+
+// Enable idempotently enables CRIO on a host
+func (r *CRIO) Enable() error {
+	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
+		return err
+	}
+	return r.Runner.Run("sudo systemctl start crio")
+}
+
+// Disable idempotently disables CRIO on a host
+func (r *CRIO) Disable() error {
+	return r.Runner.Run("sudo systemctl stop crio")
+}
+
+// KubeletOptions returns kubelet options for a runtime.
+func (r *CRIO) KubeletOptions() map[string]string {
+	return criKubeletOptions(r.SocketPath())
+}
+
+// LoadImage loads an image into this runtime. CRI-O shares its image storage with kpod.
+func (r *CRIO) LoadImage(path string) error {
+	glog.Infof("Loading image: %s", path)
+	if err := r.Runner.Run(fmt.Sprintf("sudo kpod load -i %s", path)); err != nil {
+		return errors.Wrapf(err, "loading cri-o image: %s", path)
+	}
+	return nil
+}
+
+// ListContainers returns a list of managed by this container runtime
+func (r *CRIO) ListContainers(filter string) ([]string, error) {
+	return listCRIContainers(r.Runner, filter)
+}
+
+// KillContainers removes containers based on ID
+func (r *CRIO) KillContainers(ids []string) error {
+	return killCRIContainers(r.Runner, ids)
+}
+
+// StopContainers stops containers based on ID
+func (r *CRIO) StopContainers(ids []string) error {
+	return stopCRIContainers(r.Runner, ids)
+}
+
+// ContainerLogs returns the command to retrieve the log for a container based on ID
+func (r *CRIO) ContainerLogs(id string, len int, follow bool) string {
+	return criContainerLogs(id, len, follow)
+}
+
+// ContainerExec returns the command to run a command in a container based on ID
+func (r *CRIO) ContainerExec(id string, command string) string {
+	return criContainerExec(id, command)
+}
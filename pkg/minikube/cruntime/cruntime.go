@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cruntime contains code specific to the container runtimes minikube can run Kubernetes on.
+package cruntime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommandRunner is the subset of bootstrapper.CommandRunner this package consumes
+type CommandRunner interface {
+	Run(string) error
+	CombinedOutput(string) (string, error)
+}
+
+// Manager is a common interface for container runtimes
+type Manager interface {
+	// Name is a human readable name for the runtime
+	Name() string
+	// Enable enables and starts the runtime
+	Enable() error
+	// Disable stops the runtime
+	Disable() error
+	// Active returns whether or not the runtime is running
+	Active() bool
+	// Available returns an error if the runtime can't be used on this host
+	Available() error
+
+	// KubeletOptions returns the kubelet flags needed to use this runtime
+	KubeletOptions() map[string]string
+	// SocketPath returns the path to the socket the runtime listens on
+	SocketPath() string
+
+	// LoadImage loads an image archive that is already on the host into the runtime
+	LoadImage(string) error
+
+	// ListContainers returns the IDs of Kubernetes containers whose name matches the filter
+	ListContainers(string) ([]string, error)
+	// KillContainers removes containers based on their ID
+	KillContainers([]string) error
+	// StopContainers stops containers based on their ID
+	StopContainers([]string) error
+	// ContainerLogs returns the command to retrieve the log for a container based on its ID
+	ContainerLogs(id string, len int, follow bool) string
+	// ContainerExec returns the command to run a command in a running container based on its ID
+	ContainerExec(id string, command string) string
+}
+
+// Config is runtime configuration
+type Config struct {
+	// Type of runtime to create ("docker", "crio", "containerd")
+	Type string
+	// Custom path to a socket file
+	Socket string
+	// Runner is the CommandRunner object to execute commands with
+	Runner CommandRunner
+	// RegistryMirrors are the registry mirrors of runtimes configured by minikube
+	RegistryMirrors []string
+	// InsecureRegistries are the insecure registries of runtimes configured by minikube
+	InsecureRegistries []string
+}
+
+// New returns an appropriately configured runtime
+func New(c Config) (Manager, error) {
+	switch c.Type {
+	case "", "docker":
+		return &Docker{Socket: c.Socket, Runner: c.Runner}, nil
+	case "crio", "cri-o":
+		return &CRIO{Socket: c.Socket, Runner: c.Runner}, nil
+	case "containerd":
+		return &Containerd{Socket: c.Socket, Runner: c.Runner, RegistryMirrors: c.RegistryMirrors, InsecureRegistries: c.InsecureRegistries}, nil
+	default:
+		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
+	}
+}
+
+// IsSupported returns whether minikube knows how to manage the runtime
+func IsSupported(runtime string) bool {
+	_, err := New(Config{Type: runtime})
+	return err == nil
+}
+
+// isServiceActive returns whether a systemd service is running
+func isServiceActive(cr CommandRunner, name string) bool {
+	return cr.Run(fmt.Sprintf("systemctl is-active --quiet service %s", name)) == nil
+}
+
+// splitIDs returns the container IDs printed one per line by a runtime CLI
+func splitIDs(output string) []string {
+	var ids []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			ids = append(ids, line)
+		}
+	}
+	return ids
+}
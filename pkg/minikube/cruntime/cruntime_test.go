@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRunner records the commands it runs and returns canned output.
+// Commands starting with a prefix in fail return an error.
+type fakeRunner struct {
+	cmds   []string
+	output map[string]string
+	fail   []string
+}
+
+func (f *fakeRunner) Run(cmd string) error {
+	_, err := f.CombinedOutput(cmd)
+	return err
+}
+
+func (f *fakeRunner) CombinedOutput(cmd string) (string, error) {
+	f.cmds = append(f.cmds, cmd)
+	for _, prefix := range f.fail {
+		if strings.HasPrefix(cmd, prefix) {
+			return "", fmt.Errorf("%s failed", cmd)
+		}
+	}
+	for prefix, out := range f.output {
+		if strings.HasPrefix(cmd, prefix) {
+			return out, nil
+		}
+	}
+	return "", nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		runtime   string
+		name      string
+		socket    string
+		shouldErr bool
+	}{
+		{runtime: "", name: "Docker", socket: "/var/run/docker.sock"},
+		{runtime: "docker", name: "Docker", socket: "/var/run/docker.sock"},
+		{runtime: "crio", name: "CRI-O", socket: "/var/run/crio/crio.sock"},
+		{runtime: "cri-o", name: "CRI-O", socket: "/var/run/crio/crio.sock"},
+		{runtime: "containerd", name: "containerd", socket: "/run/containerd/containerd.sock"},
+		{runtime: "rkt", shouldErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.runtime, func(t *testing.T) {
+			r, err := New(Config{Type: test.runtime})
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if IsSupported(test.runtime) == test.shouldErr {
+				t.Errorf("IsSupported(%q) = %t", test.runtime, !test.shouldErr)
+			}
+			if test.shouldErr {
+				return
+			}
+			if r.Name() != test.name {
+				t.Errorf("Expected name %s, got %s", test.name, r.Name())
+			}
+			if r.SocketPath() != test.socket {
+				t.Errorf("Expected socket %s, got %s", test.socket, r.SocketPath())
+			}
+		})
+	}
+}
+
+func TestKubeletOptions(t *testing.T) {
+	r, _ := New(Config{Type: "crio"})
+	expected := map[string]string{
+		"container-runtime":          "remote",
+		"container-runtime-endpoint": "/var/run/crio/crio.sock",
+		"image-service-endpoint":     "/var/run/crio/crio.sock",
+		"runtime-request-timeout":    "15m",
+	}
+	if actual := r.KubeletOptions(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
+
+func TestListAndKillContainers(t *testing.T) {
+	tests := []struct {
+		runtime string
+		list    string
+		kill    string
+	}{
+		{
+			runtime: "docker",
+			list:    "docker ps",
+			kill:    "docker rm -f abc def",
+		},
+		{
+			runtime: "crio",
+			list:    "sudo crictl ps",
+			kill:    "sudo crictl rm -f abc def",
+		},
+		{
+			runtime: "containerd",
+			list:    "sudo crictl ps",
+			kill:    "sudo crictl rm -f abc def",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.runtime, func(t *testing.T) {
+			f := &fakeRunner{output: map[string]string{test.list: "abc\n\ndef\n"}}
+			r, err := New(Config{Type: test.runtime, Runner: f})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			ids, err := r.ListContainers("")
+			if err != nil {
+				t.Fatalf("Unexpected error listing containers: %s", err)
+			}
+			if !reflect.DeepEqual(ids, []string{"abc", "def"}) {
+				t.Fatalf("Unexpected container ids: %v", ids)
+			}
+			if err := r.KillContainers(ids); err != nil {
+				t.Fatalf("Unexpected error killing containers: %s", err)
+			}
+			if last := f.cmds[len(f.cmds)-1]; last != test.kill {
+				t.Errorf("Expected %q, got %q", test.kill, last)
+			}
+		})
+	}
+}
+
+func TestContainerLogs(t *testing.T) {
+	r, _ := New(Config{Type: "docker"})
+	expected := fmt.Sprintf("docker logs --tail %d --follow abc", 50)
+	if actual := r.ContainerLogs("abc", 50, true); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
+
+func TestContainerExec(t *testing.T) {
+	tests := []struct {
+		runtime  string
+		expected string
+	}{
+		{runtime: "docker", expected: "docker exec abc etcdctl defrag"},
+		{runtime: "crio", expected: "sudo crictl exec abc etcdctl defrag"},
+		{runtime: "containerd", expected: "sudo crictl exec abc etcdctl defrag"},
+	}
+	for _, test := range tests {
+		r, _ := New(Config{Type: test.runtime})
+		if actual := r.ContainerExec("abc", "etcdctl defrag"); actual != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.runtime, test.expected, actual)
+		}
+	}
+}
+
+func TestLoadImage(t *testing.T) {
+	tests := []struct {
+		runtime  string
+		expected string
+	}{
+		{runtime: "docker", expected: "docker load -i /tmp/img"},
+		{runtime: "crio", expected: "sudo kpod load -i /tmp/img"},
+		{runtime: "containerd", expected: "sudo ctr --namespace=k8s.io images import /tmp/img"},
+	}
+	for _, test := range tests {
+		t.Run(test.runtime, func(t *testing.T) {
+			f := &fakeRunner{}
+			r, _ := New(Config{Type: test.runtime, Runner: f})
+			if err := r.LoadImage("/tmp/img"); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(f.cmds, []string{test.expected}) {
+				t.Errorf("Expected %q, got %v", test.expected, f.cmds)
+			}
+		})
+	}
+}
+
+func TestGenerateContainerdConfig(t *testing.T) {
+	c, err := GenerateContainerdConfig([]string{"https://mirror.example.com"}, []string{"10.0.0.0/24", "registry.local:5000"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		`address = "/run/containerd/containerd.sock"`,
+		`endpoint = ["https://mirror.example.com", "https://registry-1.docker.io"]`,
+		`[plugins.cri.registry.mirrors."registry.local:5000"]`,
+		`endpoint = ["http://registry.local:5000"]`,
+	} {
+		if !strings.Contains(c, expected) {
+			t.Errorf("Expected config to contain %s:\n%s", expected, c)
+		}
+	}
+	if strings.Contains(c, "10.0.0.0/24") {
+		t.Errorf("Expected CIDR to be skipped:\n%s", c)
+	}
+}
+
+func TestContainerdEnable(t *testing.T) {
+	tests := []struct {
+		description string
+		fail        []string
+		expected    string
+	}{
+		{description: "changed", fail: []string{"sudo cmp"}, expected: "sudo systemctl restart containerd"},
+		{description: "unchanged", expected: "sudo systemctl start containerd"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			f := &fakeRunner{fail: test.fail}
+			r, _ := New(Config{Type: "containerd", Runner: f, RegistryMirrors: []string{"https://mirror.example.com"}})
+			if err := r.Enable(); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			var wrote bool
+			for _, cmd := range f.cmds {
+				if strings.Contains(cmd, "base64 -d | sudo tee "+ContainerdConfigFile+".new") {
+					wrote = true
+				}
+			}
+			if !wrote {
+				t.Errorf("Expected %s to be written, ran %v", ContainerdConfigFile, f.cmds)
+			}
+			if last := f.cmds[len(f.cmds)-1]; last != test.expected {
+				t.Errorf("Expected %q last, got %q", test.expected, last)
+			}
+		})
+	}
+}
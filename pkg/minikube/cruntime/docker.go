@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// Docker contains Docker runtime state
+type Docker struct {
+	Socket string
+	Runner CommandRunner
+}
+
+// Name is a human readable name for Docker
+func (r *Docker) Name() string {
+	return "Docker"
+}
+
+// SocketPath returns the path to the socket file for Docker
+func (r *Docker) SocketPath() string {
+	if r.Socket != "" {
+		return r.Socket
+	}
+	return "/var/run/docker.sock"
+}
+
+// Available returns an error if it is not possible to use this runtime on a host
+func (r *Docker) Available() error {
+	return r.Runner.Run("command -v docker")
+}
+
+// Active returns if docker is active on the host
+func (r *Docker) Active() bool {
+	return isServiceActive(r.Runner, "docker")
+}
+
+// Enable idempotently enables Docker on a host
+func (r *Docker) Enable() error {
+	return r.Runner.Run("sudo systemctl start docker")
+}
+
+// Disable idempotently disables Docker on a host
+func (r *Docker) Disable() error {
+	return r.Runner.Run("sudo systemctl stop docker docker.socket")
+}
+
+// KubeletOptions returns kubelet options for a runtime.
+func (r *Docker) KubeletOptions() map[string]string {
+	return map[string]string{
+		"container-runtime": "docker",
+	}
+}
+
+// LoadImage loads an image into this runtime
+func (r *Docker) LoadImage(path string) error {
+	glog.Infof("Loading image: %s", path)
+	if err := r.Runner.Run(fmt.Sprintf("docker load -i %s", path)); err != nil {
+		return errors.Wrapf(err, "loading docker image: %s", path)
+	}
+	return nil
+}
+
+// ListContainers returns a list of Kubernetes containers. The kubelet prefixes
+// the names of the containers it creates with k8s_.
+func (r *Docker) ListContainers(filter string) ([]string, error) {
+	content, err := r.Runner.CombinedOutput(fmt.Sprintf(`docker ps -a --filter="name=k8s_%s" --format="{{.ID}}"`, filter))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing containers")
+	}
+	return splitIDs(content), nil
+}
+
+// KillContainers forcibly removes a running container based on ID
+func (r *Docker) KillContainers(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Killing containers: %s", ids)
+	return r.Runner.Run(fmt.Sprintf("docker rm -f %s", strings.Join(ids, " ")))
+}
+
+// StopContainers stops a running container based on ID
+func (r *Docker) StopContainers(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Stopping containers: %s", ids)
+	return r.Runner.Run(fmt.Sprintf("docker stop %s", strings.Join(ids, " ")))
+}
+
+// ContainerLogs returns the command to retrieve the log for a container based on ID
+func (r *Docker) ContainerLogs(id string, len int, follow bool) string {
+	var cmd []string
+	cmd = append(cmd, "docker logs")
+	if len > 0 {
+		cmd = append(cmd, fmt.Sprintf("--tail %d", len))
+	}
+	if follow {
+		cmd = append(cmd, "--follow")
+	}
+	cmd = append(cmd, id)
+	return strings.Join(cmd, " ")
+}
+
+// ContainerExec returns the command to run a command in a container based on ID
+func (r *Docker) ContainerExec(id string, command string) string {
+	return fmt.Sprintf("docker exec %s %s", id, command)
+}
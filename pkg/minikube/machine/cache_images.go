@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -30,7 +31,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/util"
 
 	"github.com/containers/image/copy"
 	"github.com/containers/image/docker"
@@ -43,6 +46,10 @@ import (
 
 const tempLoadDir = "/tmp"
 
+// cacheWaitTimeout is how long to wait for an image that is still being cached
+// before loading it.
+var cacheWaitTimeout = 10 * time.Minute
+
 var getWindowsVolumeName = getWindowsVolumeNameCmd
 
 func CacheImagesForBootstrapper(version string, clusterBootstrapper string) error {
@@ -80,14 +87,14 @@ func CacheImages(images []string, cacheDir string) error {
 	return nil
 }
 
-func LoadImages(cmd bootstrapper.CommandRunner, images []string, cacheDir string) error {
+func LoadImages(cmd bootstrapper.CommandRunner, cr cruntime.Manager, images []string, cacheDir string) error {
 	var g errgroup.Group
 	for _, image := range images {
 		image := image
 		g.Go(func() error {
 			src := filepath.Join(cacheDir, image)
 			src = sanitizeCacheDir(src)
-			if err := LoadFromCacheBlocking(cmd, src); err != nil {
+			if err := LoadFromCacheBlocking(cmd, cr, src); err != nil {
 				return errors.Wrapf(err, "loading image %s", src)
 			}
 			return nil
@@ -100,7 +107,7 @@ func LoadImages(cmd bootstrapper.CommandRunner, images []string, cacheDir string
 	return nil
 }
 
-func CacheAndLoadImages(images []string) error {
+func CacheAndLoadImages(images []string, containerRuntime string) error {
 	if err := CacheImages(images, constants.ImageCacheDir); err != nil {
 		return err
 	}
@@ -122,8 +129,12 @@ func CacheAndLoadImages(images []string) error {
 	if err != nil {
 		return err
 	}
+	cr, err := cruntime.New(cruntime.Config{Type: containerRuntime, Runner: cmdRunner})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
 
-	return LoadImages(cmdRunner, images, constants.ImageCacheDir)
+	return LoadImages(cmdRunner, cr, images, constants.ImageCacheDir)
 }
 
 // # ParseReference cannot have a : in the directory path
@@ -190,13 +201,14 @@ func getWindowsVolumeNameCmd(d string) (string, error) {
 	return vname, nil
 }
 
-func LoadFromCacheBlocking(cmd bootstrapper.CommandRunner, src string) error {
+func LoadFromCacheBlocking(cmd bootstrapper.CommandRunner, cr cruntime.Manager, src string) error {
 	glog.Infoln("Loading image from cache at ", src)
 	filename := filepath.Base(src)
-	for {
-		if _, err := os.Stat(src); err == nil {
-			break
-		}
+	if err := waitForCachedImage(src, cacheWaitTimeout, time.Second); err != nil {
+		return err
+	}
+	if err := util.VerifyChecksumFile(src); err != nil {
+		return errors.Wrapf(err, "verifying cached image: %s", filename)
 	}
 	dst := filepath.Join(tempLoadDir, filename)
 	f, err := assets.NewFileAsset(src, tempLoadDir, filename, "0777")
@@ -207,26 +219,39 @@ func LoadFromCacheBlocking(cmd bootstrapper.CommandRunner, src string) error {
 		return errors.Wrap(err, "transferring cached image")
 	}
 
-	dockerLoadCmd := "docker load -i " + dst
-
-	if err := cmd.Run(dockerLoadCmd); err != nil {
-		return errors.Wrapf(err, "loading docker image: %s", dst)
+	if err := cr.LoadImage(dst); err != nil {
+		return errors.Wrapf(err, "%s load %s", cr.Name(), dst)
 	}
 
 	if err := cmd.Run("sudo rm -rf " + dst); err != nil {
-		return errors.Wrap(err, "deleting temp docker image location")
+		return errors.Wrap(err, "deleting temp image location")
 	}
 
 	glog.Infof("Successfully loaded image %s from cache", src)
 	return nil
 }
 
+// waitForCachedImage waits until the image at src has been completely cached.
+// It waits for the checksum rather than the image, which is written first.
+func waitForCachedImage(src string, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := os.Stat(util.ChecksumFilepath(src)); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out after %s waiting for %s to be cached", timeout, src)
+		}
+		time.Sleep(interval)
+	}
+}
+
 func DeleteFromImageCacheDir(images []string) error {
 	for _, image := range images {
 		path := filepath.Join(constants.ImageCacheDir, image)
 		path = sanitizeCacheDir(path)
 		glog.Infoln("Deleting image in cache at ", path)
-		if err := os.Remove(path); err != nil {
+		if err := util.RemoveCachedFile(path); err != nil {
 			return err
 		}
 	}
@@ -291,7 +316,14 @@ func _getDstRef(image, dst string) (types.ImageReference, error) {
 func CacheImage(image, dst string) error {
 	glog.Infof("Attempting to cache image: %s at %s\n", image, dst)
 	if _, err := os.Stat(dst); err == nil {
-		return nil
+		verifyErr := util.VerifyChecksumFile(dst)
+		if verifyErr == nil {
+			return nil
+		}
+		glog.Infof("Cached image %s failed verification, caching again: %s", image, verifyErr)
+		if err := util.RemoveCachedFile(dst); err != nil {
+			return errors.Wrapf(err, "removing cached image: %s", dst)
+		}
 	}
 
 	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
@@ -333,5 +365,10 @@ func CacheImage(image, dst string) error {
 		return errors.Wrap(err, "copying image")
 	}
 
+	// The checksum is written last, so its presence marks the image as completely cached
+	if err := util.WriteChecksumFile(dst); err != nil {
+		return errors.Wrap(err, "writing image checksum")
+	}
+
 	return nil
 }
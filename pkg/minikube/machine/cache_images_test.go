@@ -19,11 +19,14 @@ package machine
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
 )
 
 func TestGetSrcRef(t *testing.T) {
@@ -99,3 +102,28 @@ func TestHasWindowsDriveLetter(t *testing.T) {
 		}
 	}
 }
+
+func TestWaitForCachedImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "image")
+	if err := ioutil.WriteFile(src, []byte("image"), 0644); err != nil {
+		t.Fatalf("Error writing image: %s", err)
+	}
+	// An image without a checksum was cached before checksums or by a failed run
+	if err := waitForCachedImage(src, 50*time.Millisecond, 10*time.Millisecond); err == nil {
+		t.Errorf("Expected timeout waiting for an image without a checksum")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		util.WriteChecksumFile(src)
+	}()
+	if err := waitForCachedImage(src, 5*time.Second, 10*time.Millisecond); err != nil {
+		t.Errorf("Error waiting for cached image: %s", err)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// ChecksumFilepath returns the path of the SHA-256 manifest stored next to a cached artifact.
+func ChecksumFilepath(path string) string {
+	return path + constants.ShaSuffix
+}
+
+// IsChecksumFile returns true if the path is a SHA-256 manifest rather than a cached artifact.
+func IsChecksumFile(path string) bool {
+	return strings.HasSuffix(path, constants.ShaSuffix)
+}
+
+// GetSha256Checksum returns the hex encoded SHA-256 digest of the file at path.
+func GetSha256Checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "hashing %s", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksumFile computes the SHA-256 digest of the file at path and writes it to the
+// manifest next to it, in the same format used by sha256sum.
+func WriteChecksumFile(path string) error {
+	sum, err := GetSha256Checksum(path)
+	if err != nil {
+		return err
+	}
+	return writeChecksumFile(path, sum)
+}
+
+func writeChecksumFile(path, sum string) error {
+	data := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+	if err := ioutil.WriteFile(ChecksumFilepath(path), []byte(data), 0644); err != nil {
+		return errors.Wrapf(err, "writing checksum for %s", path)
+	}
+	return nil
+}
+
+// ReadChecksumFile returns the digest recorded in the manifest for the file at path.
+func ReadChecksumFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(ChecksumFilepath(path))
+	if err != nil {
+		return "", errors.Wrapf(err, "reading checksum for %s", path)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum for %s is empty", path)
+	}
+	return fields[0], nil
+}
+
+// VerifyChecksumFile checks the file at path against the digest recorded in its manifest.
+// A missing manifest is treated as a verification failure.
+func VerifyChecksumFile(path string) error {
+	expected, err := ReadChecksumFile(path)
+	if err != nil {
+		return err
+	}
+	actual, err := GetSha256Checksum(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("checksum mismatch for %s, expected: %s actual: %s", path, expected, actual)
+	}
+	return nil
+}
+
+// UpdateChecksumFile replaces the manifest of the file at path with the checksum
+// published at checksumURL.
+func UpdateChecksumFile(path, checksumURL string) error {
+	published, err := ParseSHAFromURL(checksumURL)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(published)
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum at %s is empty", checksumURL)
+	}
+	return writeChecksumFile(path, fields[0])
+}
+
+// VerifyCachedFile checks a cached artifact that was downloaded atomically.
+// If checksumURL is set the artifact is checked against the published checksum,
+// falling back to its manifest when the checksum can't be downloaded. Otherwise
+// artifacts cached before manifests were written get one recorded now.
+func VerifyCachedFile(path, checksumURL string) error {
+	if checksumURL != "" {
+		if err := UpdateChecksumFile(path, checksumURL); err != nil {
+			glog.Warningf("Unable to download checksum for %s, verifying against the cached checksum: %s", path, err)
+		}
+	} else if _, err := os.Stat(ChecksumFilepath(path)); os.IsNotExist(err) {
+		glog.Infof("Recording checksum for %s, which was cached without one", path)
+		if err := WriteChecksumFile(path); err != nil {
+			return err
+		}
+	}
+	return VerifyChecksumFile(path)
+}
+
+// RemoveCachedFile deletes a cached artifact along with its manifest.
+func RemoveCachedFile(path string) error {
+	for _, p := range []string{path, ChecksumFilepath(path)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing %s", p)
+		}
+	}
+	return nil
+}
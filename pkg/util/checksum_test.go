@@ -0,0 +1,133 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "kubelet")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+
+	if err := VerifyChecksumFile(path); err == nil {
+		t.Fatalf("Expected an error verifying a file without a checksum")
+	}
+
+	if err := WriteChecksumFile(path); err != nil {
+		t.Fatalf("Error writing checksum: %s", err)
+	}
+	sum, err := ReadChecksumFile(path)
+	if err != nil {
+		t.Fatalf("Error reading checksum: %s", err)
+	}
+	expected := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if sum != expected {
+		t.Fatalf("Expected checksum %s, got %s", expected, sum)
+	}
+	if err := VerifyChecksumFile(path); err != nil {
+		t.Fatalf("Unexpected error verifying checksum: %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("hell"), 0644); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+	if err := VerifyChecksumFile(path); err == nil {
+		t.Fatalf("Expected an error verifying a truncated file")
+	}
+
+	if err := RemoveCachedFile(path); err != nil {
+		t.Fatalf("Error removing cached file: %s", err)
+	}
+	for _, p := range []string{path, ChecksumFilepath(path)} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Fatalf("Expected %s to be removed", p)
+		}
+	}
+}
+
+func TestVerifyCachedFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	published := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/kubelet.sha256" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, published+"\n")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(tempDir, "kubelet")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+
+	// A file cached without a checksum is verified against the published one
+	if err := VerifyCachedFile(path, server.URL+"/kubelet.sha256"); err != nil {
+		t.Fatalf("Unexpected error verifying against the published checksum: %s", err)
+	}
+
+	// A locally recorded checksum isn't trusted over the published one
+	if err := ioutil.WriteFile(path, []byte("hell"), 0644); err != nil {
+		t.Fatalf("Error writing file: %s", err)
+	}
+	if err := WriteChecksumFile(path); err != nil {
+		t.Fatalf("Error writing checksum: %s", err)
+	}
+	if err := VerifyCachedFile(path, server.URL+"/kubelet.sha256"); err == nil {
+		t.Fatalf("Expected an error verifying a file that doesn't match the published checksum")
+	}
+
+	// The cached checksum is used when the published one can't be downloaded
+	if err := WriteChecksumFile(path); err != nil {
+		t.Fatalf("Error writing checksum: %s", err)
+	}
+	if err := VerifyCachedFile(path, server.URL+"/missing.sha256"); err != nil {
+		t.Fatalf("Unexpected error verifying against the cached checksum: %s", err)
+	}
+
+	// Without a published checksum, a file cached without one gets a checksum recorded
+	if err := os.Remove(ChecksumFilepath(path)); err != nil {
+		t.Fatalf("Error removing checksum: %s", err)
+	}
+	if err := VerifyCachedFile(path, ""); err != nil {
+		t.Fatalf("Unexpected error verifying a file cached without a checksum: %s", err)
+	}
+	if _, err := ReadChecksumFile(path); err != nil {
+		t.Fatalf("Expected a checksum to be recorded: %s", err)
+	}
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/blang/semver"
+)
+
+// ComponentFlag describes a command line flag of a Kubernetes component and
+// the versions it exists in.
+type ComponentFlag struct {
+	Name string
+	// Added is the first version with the flag. The zero value means all versions.
+	Added semver.Version
+	// Deprecated is the first version that warns about the flag.
+	Deprecated semver.Version
+	// Removed is the first version without the flag.
+	Removed semver.Version
+	// Replacement is the flag to use instead of a deprecated or removed flag, if any.
+	Replacement string
+}
+
+func newComponentFlags(names ...string) []ComponentFlag {
+	var f []ComponentFlag
+	for _, n := range names {
+		f = append(f, ComponentFlag{Name: n})
+	}
+	return f
+}
+
+func mustVersion(s string) semver.Version {
+	return semver.MustParse(s)
+}
+
+// Flags every Kubernetes component accepts for logging
+var commonFlags = newComponentFlags(
+	"alsologtostderr", "log-backtrace-at", "log-dir", "log-flush-frequency", "logtostderr",
+	"stderrthreshold", "v", "vmodule", "version",
+)
+
+// componentFlags are the flags of each component minikube can pass extra config to
+var componentFlags = map[string][]ComponentFlag{
+	"kubelet": append(newComponentFlags(
+		"address", "allow-privileged", "anonymous-auth", "authentication-token-webhook",
+		"authentication-token-webhook-cache-ttl", "authorization-mode", "authorization-webhook-cache-authorized-ttl",
+		"authorization-webhook-cache-unauthorized-ttl", "azure-container-registry-config", "bootstrap-kubeconfig",
+		"cert-dir", "cgroup-driver", "cgroup-root", "cgroups-per-qos", "chaos-chance", "client-ca-file",
+		"cloud-config", "cloud-provider", "cluster-dns", "cluster-domain", "cni-bin-dir", "cni-conf-dir",
+		"container-runtime", "container-runtime-endpoint", "containerized", "contention-profiling",
+		"cpu-cfs-quota", "docker-disable-shared-pid", "docker-endpoint", "enable-controller-attach-detach",
+		"enable-debugging-handlers", "enable-server", "enforce-node-allocatable", "event-burst", "event-qps",
+		"eviction-hard", "eviction-max-pod-grace-period", "eviction-minimum-reclaim", "eviction-pressure-transition-period",
+		"eviction-soft", "eviction-soft-grace-period", "exit-on-lock-contention", "experimental-allocatable-ignore-eviction",
+		"experimental-check-node-capabilities-before-mount", "experimental-kernel-memcg-notification",
+		"experimental-mounter-path", "fail-swap-on", "feature-gates", "file-check-frequency", "hairpin-mode",
+		"healthz-bind-address", "healthz-port", "host-ipc-sources", "host-network-sources", "host-pid-sources",
+		"hostname-override", "http-check-frequency", "image-gc-high-threshold", "image-gc-low-threshold",
+		"image-pull-progress-deadline", "image-service-endpoint", "iptables-drop-bit", "iptables-masquerade-bit",
+		"keep-terminated-pod-volumes", "kube-api-burst", "kube-api-content-type", "kube-api-qps", "kube-reserved",
+		"kube-reserved-cgroup", "kubeconfig", "kubelet-cgroups", "lock-file", "make-iptables-util-chains",
+		"manifest-url", "manifest-url-header", "max-open-files", "max-pods", "minimum-image-ttl-duration",
+		"network-plugin", "network-plugin-mtu", "node-ip", "node-labels", "node-status-update-frequency",
+		"oom-score-adj", "pod-cidr", "pod-infra-container-image", "pod-manifest-path", "pods-per-core", "port",
+		"protect-kernel-defaults", "provider-id", "read-only-port", "register-node", "register-with-taints",
+		"registry-burst", "registry-qps", "resolv-conf", "root-dir", "rotate-certificates", "runonce",
+		"runtime-cgroups", "runtime-request-timeout", "seccomp-profile-root", "serialize-image-pulls",
+		"streaming-connection-idle-timeout", "sync-frequency", "system-cgroups", "system-reserved",
+		"system-reserved-cgroup", "tls-cert-file", "tls-private-key-file", "volume-plugin-dir",
+		"volume-stats-agg-period",
+	),
+		ComponentFlag{Name: "api-servers", Removed: mustVersion("1.8.0-alpha.0"), Replacement: "kubeconfig"},
+		ComponentFlag{Name: "cadvisor-port", Deprecated: mustVersion("1.10.0-alpha.0"), Removed: mustVersion("1.12.0-alpha.0")},
+		ComponentFlag{Name: "config", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "cpu-manager-policy", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "cpu-manager-reconcile-period", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "dynamic-config-dir", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "enable-custom-metrics", Deprecated: mustVersion("1.8.0-alpha.0"), Removed: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "experimental-bootstrap-kubeconfig", Deprecated: mustVersion("1.6.0"), Removed: mustVersion("1.9.0-alpha.0"), Replacement: "bootstrap-kubeconfig"},
+		ComponentFlag{Name: "require-kubeconfig", Deprecated: mustVersion("1.8.0-alpha.0"), Removed: mustVersion("1.10.0-alpha.0"), Replacement: "kubeconfig"},
+		ComponentFlag{Name: "rotate-server-certificates", Added: mustVersion("1.9.0-alpha.0")},
+	),
+
+	"apiserver": append(newComponentFlags(
+		"admission-control-config-file", "advertise-address", "allow-privileged", "anonymous-auth",
+		"apiserver-count", "audit-log-format", "audit-log-maxage", "audit-log-maxbackup", "audit-log-maxsize",
+		"audit-log-path", "audit-policy-file", "audit-webhook-config-file", "audit-webhook-initial-backoff",
+		"authentication-token-webhook-cache-ttl", "authentication-token-webhook-config-file", "authorization-mode",
+		"authorization-policy-file", "authorization-webhook-cache-authorized-ttl",
+		"authorization-webhook-cache-unauthorized-ttl", "authorization-webhook-config-file", "basic-auth-file",
+		"bind-address", "cert-dir", "client-ca-file", "cloud-config", "cloud-provider", "contention-profiling",
+		"cors-allowed-origins", "default-not-ready-toleration-seconds", "default-unreachable-toleration-seconds",
+		"default-watch-cache-size", "delete-collection-workers", "deserialization-cache-size", "enable-aggregator-routing",
+		"enable-garbage-collector", "enable-logs-handler", "enable-swagger-ui", "endpoint-reconciler-type",
+		"etcd-cafile", "etcd-certfile", "etcd-compaction-interval", "etcd-keyfile", "etcd-prefix", "etcd-servers",
+		"etcd-servers-overrides", "event-ttl", "experimental-encryption-provider-config", "external-hostname",
+		"feature-gates", "insecure-bind-address", "insecure-port", "kubelet-certificate-authority",
+		"kubelet-client-certificate", "kubelet-client-key", "kubelet-https", "kubelet-preferred-address-types",
+		"kubelet-read-only-port", "kubelet-timeout", "kubernetes-service-node-port", "max-connection-bytes-per-sec",
+		"max-mutating-requests-inflight", "max-requests-inflight", "min-request-timeout", "oidc-ca-file",
+		"oidc-client-id", "oidc-groups-claim", "oidc-groups-prefix", "oidc-issuer-url", "oidc-username-claim",
+		"oidc-username-prefix", "profiling", "proxy-client-cert-file", "proxy-client-key-file",
+		"repair-malformed-updates", "request-timeout", "requestheader-allowed-names", "requestheader-client-ca-file",
+		"requestheader-extra-headers-prefix", "requestheader-group-headers", "requestheader-username-headers",
+		"runtime-config", "secure-port", "service-account-key-file", "service-account-lookup",
+		"service-cluster-ip-range", "service-node-port-range", "storage-backend", "storage-media-type",
+		"storage-versions", "target-ram-mb", "tls-ca-file", "tls-cert-file", "tls-cipher-suites",
+		"tls-min-version", "tls-private-key-file", "tls-sni-cert-key", "token-auth-file", "watch-cache",
+		"watch-cache-sizes",
+	),
+		ComponentFlag{Name: "admission-control", Deprecated: mustVersion("1.10.0-alpha.0"), Replacement: "enable-admission-plugins"},
+		ComponentFlag{Name: "audit-log-batch-max-size", Added: mustVersion("1.9.0-alpha.0")},
+		ComponentFlag{Name: "audit-log-mode", Added: mustVersion("1.9.0-alpha.0")},
+		ComponentFlag{Name: "audit-webhook-batch-max-size", Added: mustVersion("1.9.0-alpha.0")},
+		ComponentFlag{Name: "audit-webhook-mode", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "disable-admission-plugins", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "enable-admission-plugins", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "enable-bootstrap-token-auth", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "etcd-quorum-read", Deprecated: mustVersion("1.9.0-alpha.0")},
+		ComponentFlag{Name: "experimental-bootstrap-token-auth", Deprecated: mustVersion("1.8.0-alpha.0"), Removed: mustVersion("1.9.0-alpha.0"), Replacement: "enable-bootstrap-token-auth"},
+		ComponentFlag{Name: "experimental-keystone-url", Deprecated: mustVersion("1.8.0-alpha.0"), Removed: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "service-account-api-audiences", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "service-account-issuer", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "service-account-signing-key-file", Added: mustVersion("1.10.0-alpha.0")},
+	),
+
+	"controller-manager": append(newComponentFlags(
+		"address", "allocate-node-cidrs", "attach-detach-reconcile-sync-period", "cloud-config", "cloud-provider",
+		"cluster-cidr", "cluster-name", "cluster-signing-cert-file", "cluster-signing-key-file",
+		"concurrent-deployment-syncs", "concurrent-endpoint-syncs", "concurrent-gc-syncs",
+		"concurrent-namespace-syncs", "concurrent-replicaset-syncs", "concurrent-resource-quota-syncs",
+		"concurrent-service-syncs", "concurrent-serviceaccount-token-syncs", "concurrent_rc_syncs",
+		"configure-cloud-routes", "contention-profiling", "controller-start-interval", "controllers",
+		"deployment-controller-sync-period", "disable-attach-detach-reconcile-sync", "enable-dynamic-provisioning",
+		"enable-garbage-collector", "enable-hostpath-provisioner", "enable-taint-manager", "experimental-cluster-signing-duration",
+		"feature-gates", "flex-volume-plugin-dir", "horizontal-pod-autoscaler-downscale-delay",
+		"horizontal-pod-autoscaler-sync-period", "horizontal-pod-autoscaler-tolerance",
+		"horizontal-pod-autoscaler-upscale-delay", "horizontal-pod-autoscaler-use-rest-clients",
+		"insecure-experimental-approve-all-kubelet-csrs-for-group", "kube-api-burst", "kube-api-content-type",
+		"kube-api-qps", "kubeconfig", "large-cluster-size-threshold", "leader-elect", "leader-elect-lease-duration",
+		"leader-elect-renew-deadline", "leader-elect-resource-lock", "leader-elect-retry-period", "master",
+		"min-resync-period", "namespace-sync-period", "node-cidr-mask-size", "node-eviction-rate",
+		"node-monitor-grace-period", "node-monitor-period", "node-startup-grace-period", "pod-eviction-timeout", "port", "profiling",
+		"pv-recycler-increment-timeout-nfs", "pv-recycler-minimum-timeout-hostpath", "pv-recycler-minimum-timeout-nfs",
+		"pv-recycler-pod-template-filepath-hostpath", "pv-recycler-pod-template-filepath-nfs",
+		"pv-recycler-timeout-increment-hostpath", "pvclaimbinder-sync-period", "resource-quota-sync-period",
+		"root-ca-file", "route-reconciliation-period", "secondary-node-eviction-rate", "service-account-private-key-file",
+		"service-cluster-ip-range", "terminated-pod-gc-threshold", "unhealthy-zone-threshold",
+		"use-service-account-credentials",
+	),
+		ComponentFlag{Name: "bind-address", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "cert-dir", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "secure-port", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "tls-cert-file", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "tls-private-key-file", Added: mustVersion("1.10.0-alpha.0")},
+	),
+
+	"scheduler": append(newComponentFlags(
+		"address", "algorithm-provider", "contention-profiling", "failure-domains", "feature-gates",
+		"hard-pod-affinity-symmetric-weight", "kube-api-burst", "kube-api-content-type", "kube-api-qps",
+		"kubeconfig", "leader-elect", "leader-elect-lease-duration", "leader-elect-renew-deadline",
+		"leader-elect-resource-lock", "leader-elect-retry-period", "lock-object-name", "lock-object-namespace",
+		"master", "policy-config-file", "policy-configmap", "policy-configmap-namespace", "port", "profiling",
+		"scheduler-name", "use-legacy-policy-config",
+	),
+		ComponentFlag{Name: "config", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "write-config-to", Added: mustVersion("1.10.0-alpha.0")},
+	),
+
+	"proxy": append(newComponentFlags(
+		"bind-address", "cleanup-iptables", "cluster-cidr", "config", "config-sync-period", "conntrack-max-per-core",
+		"conntrack-min", "conntrack-tcp-timeout-close-wait", "conntrack-tcp-timeout-established", "feature-gates",
+		"healthz-bind-address", "healthz-port", "hostname-override", "iptables-masquerade-bit",
+		"iptables-min-sync-period", "iptables-sync-period", "kube-api-burst", "kube-api-content-type", "kube-api-qps",
+		"kubeconfig", "masquerade-all", "master", "metrics-bind-address", "oom-score-adj", "profiling", "proxy-mode",
+		"proxy-port-range", "resource-container", "udp-timeout",
+	),
+		ComponentFlag{Name: "cleanup", Added: mustVersion("1.9.0-alpha.0")},
+		ComponentFlag{Name: "ipvs-min-sync-period", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "ipvs-scheduler", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "ipvs-sync-period", Added: mustVersion("1.8.0-alpha.0")},
+		ComponentFlag{Name: "nodeport-addresses", Added: mustVersion("1.10.0-alpha.0")},
+		ComponentFlag{Name: "write-config-to", Added: mustVersion("1.8.0-alpha.0")},
+	),
+
+	// etcd is versioned independently of Kubernetes, so its flags aren't versioned here
+	"etcd": newComponentFlags(
+		"advertise-client-urls", "auto-compaction-retention", "auto-tls", "cert-file", "client-cert-auth", "cors",
+		"data-dir", "debug", "discovery", "discovery-fallback", "discovery-proxy", "discovery-srv",
+		"election-timeout", "enable-pprof", "enable-v2", "force-new-cluster", "heartbeat-interval",
+		"initial-advertise-peer-urls", "initial-cluster", "initial-cluster-state", "initial-cluster-token",
+		"key-file", "listen-client-urls", "listen-peer-urls", "log-output", "log-package-levels", "max-request-bytes",
+		"max-snapshots", "max-txn-ops", "max-wals", "metrics", "name", "peer-auto-tls", "peer-cert-file",
+		"peer-client-cert-auth", "peer-key-file", "peer-trusted-ca-file", "proxy", "quota-backend-bytes",
+		"snapshot-count", "strict-reconfig-check", "trusted-ca-file", "wal-dir",
+	),
+}
+
+// flagStyleKey matches keys that are command line flags rather than the
+// field paths used by localkube, e.g. Authorization.Mode
+var flagStyleKey = regexp.MustCompile(`^[a-z0-9]+([-_][a-z0-9]+)*$`)
+
+// IsValidatedComponent returns whether minikube knows the flags of a component
+func IsValidatedComponent(component string) bool {
+	_, ok := componentFlags[component]
+	return ok
+}
+
+// allComponentFlags returns the flags of a component, including the common ones
+func allComponentFlags(component string) []ComponentFlag {
+	var all []ComponentFlag
+	all = append(all, componentFlags[component]...)
+	return append(all, commonFlags...)
+}
+
+// findComponentFlag returns the flag with the given name, if the component has one
+func findComponentFlag(component, name string) (ComponentFlag, bool) {
+	for _, f := range allComponentFlags(component) {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return ComponentFlag{}, false
+}
+
+// ValidateExtraOption checks the key of an extra option against the flags of
+// its component.  With a nil version, or when the option removes the flag, the
+// flag only has to exist in some version.
+// It returns warnings for deprecated flags, and an error suggesting the closest
+// flag if the key isn't a flag of the component.  Keys of unknown components and
+// localkube field paths aren't validated.
+func ValidateExtraOption(e ExtraOption, version *semver.Version) ([]string, error) {
+	if !IsValidatedComponent(e.Component) || !flagStyleKey.MatchString(e.Key) {
+		return nil, nil
+	}
+
+	f, ok := findComponentFlag(e.Component, e.Key)
+	if !ok {
+		msg := fmt.Sprintf("%s is not a flag of %s", e.Key, e.Component)
+		if s := suggestComponentFlag(e.Component, e.Key); s != "" {
+			msg += fmt.Sprintf(", did you mean %s?", s)
+		} else {
+			msg += "."
+		}
+		return nil, fmt.Errorf("%s Use --force to pass it anyway.", msg)
+	}
+	// Removing a flag that doesn't apply to this version is harmless
+	if version == nil || e.Remove {
+		return nil, nil
+	}
+
+	if !isZeroVersion(f.Added) && version.LT(f.Added) {
+		return nil, fmt.Errorf("%s.%s was added in Kubernetes v%s and can't be used with v%s. Use --force to pass it anyway.", e.Component, e.Key, f.Added, version)
+	}
+	if !isZeroVersion(f.Removed) && version.GTE(f.Removed) {
+		msg := fmt.Sprintf("%s.%s was removed in Kubernetes v%s", e.Component, e.Key, f.Removed)
+		if f.Replacement != "" {
+			msg += fmt.Sprintf(", use %s.%s instead", e.Component, f.Replacement)
+		}
+		return nil, fmt.Errorf("%s. Use --force to pass it anyway.", msg)
+	}
+	if !isZeroVersion(f.Deprecated) && version.GTE(f.Deprecated) {
+		msg := fmt.Sprintf("%s.%s is deprecated since Kubernetes v%s", e.Component, e.Key, f.Deprecated)
+		if f.Replacement != "" {
+			msg += fmt.Sprintf(", use %s.%s instead", e.Component, f.Replacement)
+		}
+		return []string{msg}, nil
+	}
+	return nil, nil
+}
+
+func isZeroVersion(v semver.Version) bool {
+	return v.Equals(semver.Version{})
+}
+
+// suggestComponentFlag returns the flag of the component closest to name, or
+// an empty string if none is close enough to be a likely typo.
+func suggestComponentFlag(component, name string) string {
+	var names []string
+	for _, f := range allComponentFlags(component) {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", len(name)/3+2
+	for _, n := range names {
+		if d := levenshtein(name, n); d < bestDistance {
+			best, bestDistance = n, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+)
+
+func TestComponentFlagsAreUnique(t *testing.T) {
+	for component := range componentFlags {
+		seen := map[string]bool{}
+		for _, f := range allComponentFlags(component) {
+			if seen[f.Name] {
+				t.Errorf("Flag %s of %s is listed twice", f.Name, component)
+			}
+			seen[f.Name] = true
+		}
+	}
+}
+
+func TestValidateExtraOption(t *testing.T) {
+	v19 := semver.MustParse("1.9.0")
+	v110 := semver.MustParse("1.10.0")
+	tests := []struct {
+		description string
+		option      ExtraOption
+		version     *semver.Version
+		err         string
+		warning     string
+	}{
+		{
+			description: "valid flag",
+			option:      ExtraOption{Component: "kubelet", Key: "max-pods", Value: "5"},
+			version:     &v19,
+		},
+		{
+			description: "common flag",
+			option:      ExtraOption{Component: "scheduler", Key: "v", Value: "5"},
+		},
+		{
+			description: "typo",
+			option:      ExtraOption{Component: "apiserver", Key: "admision-control", Value: "Foo"},
+			err:         "did you mean admission-control?",
+		},
+		{
+			description: "unknown flag without suggestion",
+			option:      ExtraOption{Component: "proxy", Key: "make-it-fast", Value: "true"},
+			err:         "make-it-fast is not a flag of proxy. Use --force",
+		},
+		{
+			description: "not added yet",
+			option:      ExtraOption{Component: "apiserver", Key: "enable-admission-plugins", Value: "Foo"},
+			version:     &v19,
+			err:         "was added in Kubernetes v1.10.0-alpha.0",
+		},
+		{
+			description: "removed",
+			option:      ExtraOption{Component: "kubelet", Key: "require-kubeconfig", Value: "true"},
+			version:     &v110,
+			err:         "use kubelet.kubeconfig instead",
+		},
+		{
+			description: "deprecated",
+			option:      ExtraOption{Component: "apiserver", Key: "admission-control", Value: "Foo"},
+			version:     &v110,
+			warning:     "use apiserver.enable-admission-plugins instead",
+		},
+		{
+			description: "localkube field path",
+			option:      ExtraOption{Component: "apiserver", Key: "Authorization.Mode", Value: "RBAC"},
+			version:     &v110,
+		},
+		{
+			description: "unknown component",
+			option:      ExtraOption{Component: "foo", Key: "bar", Value: "baz"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			warnings, err := ValidateExtraOption(test.option, test.version)
+			if test.err == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("Expected error containing %q, got %v", test.err, err)
+			}
+			if w := strings.Join(warnings, "\n"); !strings.Contains(w, test.warning) || (test.warning == "" && w != "") {
+				t.Errorf("Expected warning containing %q, got %q", test.warning, w)
+			}
+		})
+	}
+}
@@ -30,6 +30,8 @@ const (
 	DefaultKubeConfigPath     = DefaultLocalkubeDirectory + "/kubeconfig"
 	DefaultDNSDomain          = "cluster.local"
 	DefaultServiceCIDR        = "10.96.0.0/12"
+	// LocalkubeHealthPort is where localkube reports the status of its components, on localhost
+	LocalkubeHealthPort = 10262
 )
 
 var DefaultAdmissionControllers = []string{
@@ -46,26 +48,92 @@ var DefaultAdmissionControllers = []string{
 
 // GetServiceClusterIP returns the first IP of the ServiceCIDR
 func GetServiceClusterIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := serviceCIDRBase(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, err
 	}
-	ip = ip.To4()
-	ip[3]++
+	ip[len(ip)-1]++
 	return ip, nil
 }
 
-// GetDNSIP returns x.x.x.10 of the service CIDR
+// GetDNSIP returns x.x.x.10 of the service CIDR, or x::a for IPv6
 func GetDNSIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := serviceCIDRBase(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, err
 	}
-	ip = ip.To4()
-	ip[3] = 10
+	ip[len(ip)-1] = 10
 	return ip, nil
 }
 
+// serviceCIDRBase returns a copy of the IP of the service CIDR, in its 4 byte form for IPv4
+func serviceCIDRBase(serviceCIDR string) (net.IP, error) {
+	ip, _, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing service cidr")
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return append(net.IP(nil), ip...), nil
+}
+
+// ValidateNetworkCIDRs checks that the service CIDR, and the pod CIDR if any, are
+// of the same IP family, don't overlap, and that the service CIDR holds the DNS IP.
+func ValidateNetworkCIDRs(serviceCIDR, podCIDR string) error {
+	_, serviceNet, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "invalid service CIDR %s", serviceCIDR)
+	}
+	dnsIP, err := GetDNSIP(serviceCIDR)
+	if err != nil {
+		return err
+	}
+	if !serviceNet.Contains(dnsIP) {
+		return errors.Errorf("service CIDR %s is too small, it must contain the DNS IP %s", serviceCIDR, dnsIP)
+	}
+	// The apiserver allocates IPv6 service IPs from at most 20 bits
+	if ones, bits := serviceNet.Mask.Size(); bits == 8*net.IPv6len && bits-ones > 20 {
+		return errors.Errorf("service CIDR %s is too large, an IPv6 range must be /108 or smaller", serviceCIDR)
+	}
+	if podCIDR == "" {
+		return nil
+	}
+	_, podNet, err := net.ParseCIDR(podCIDR)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pod CIDR %s", podCIDR)
+	}
+	if (serviceNet.IP.To4() == nil) != (podNet.IP.To4() == nil) {
+		return errors.Errorf("service CIDR %s and pod CIDR %s must both be IPv4 or both be IPv6", serviceCIDR, podCIDR)
+	}
+	if serviceNet.Contains(podNet.IP) || podNet.Contains(serviceNet.IP) {
+		return errors.Errorf("service CIDR %s overlaps with pod CIDR %s", serviceCIDR, podCIDR)
+	}
+	return nil
+}
+
+// ValidateNodeIPFamily checks that the service CIDR, and the pod CIDR if any, are
+// of the IP family of the node IP, since the node can't route the other family.
+func ValidateNodeIPFamily(nodeIP, serviceCIDR, podCIDR string) error {
+	ip := net.ParseIP(nodeIP)
+	if ip == nil {
+		return errors.Errorf("invalid node IP %s", nodeIP)
+	}
+	for _, cidr := range []string{serviceCIDR, podCIDR} {
+		if cidr == "" {
+			continue
+		}
+		cidrIP, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid CIDR %s", cidr)
+		}
+		if (cidrIP.To4() == nil) != (ip.To4() == nil) {
+			return errors.Errorf("CIDR %s is not of the IP family of the node IP %s", cidr, nodeIP)
+		}
+	}
+	return nil
+}
+
 func GetAlternateDNS(domain string) []string {
 	return []string{"kubernetes.default.svc." + domain, "kubernetes.default.svc", "kubernetes.default", "kubernetes", "localhost"}
 }
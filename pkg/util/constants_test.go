@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func TestServiceIPs(t *testing.T) {
+	var tests = []struct {
+		cidr      string
+		clusterIP string
+		dnsIP     string
+		shouldErr bool
+	}{
+		{cidr: DefaultServiceCIDR, clusterIP: "10.96.0.1", dnsIP: "10.96.0.10"},
+		{cidr: "172.20.0.0/16", clusterIP: "172.20.0.1", dnsIP: "172.20.0.10"},
+		{cidr: "fd00:10:96::/108", clusterIP: "fd00:10:96::1", dnsIP: "fd00:10:96::a"},
+		{cidr: "10.96.0.0", shouldErr: true},
+	}
+	for _, test := range tests {
+		clusterIP, err := GetServiceClusterIP(test.cidr)
+		if err != nil {
+			if !test.shouldErr {
+				t.Errorf("Unexpected error for %s: %s", test.cidr, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Errorf("Expected an error for %s", test.cidr)
+			continue
+		}
+		if !clusterIP.Equal(net.ParseIP(test.clusterIP)) {
+			t.Errorf("Cluster IP of %s: expected %s, got %s", test.cidr, test.clusterIP, clusterIP)
+		}
+		dnsIP, err := GetDNSIP(test.cidr)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %s", test.cidr, err)
+			continue
+		}
+		if !dnsIP.Equal(net.ParseIP(test.dnsIP)) {
+			t.Errorf("DNS IP of %s: expected %s, got %s", test.cidr, test.dnsIP, dnsIP)
+		}
+	}
+}
+
+func TestValidateNetworkCIDRs(t *testing.T) {
+	var tests = []struct {
+		description string
+		serviceCIDR string
+		podCIDR     string
+		shouldErr   bool
+	}{
+		{description: "defaults", serviceCIDR: DefaultServiceCIDR},
+		{description: "pod cidr", serviceCIDR: "172.20.0.0/16", podCIDR: "172.21.0.0/16"},
+		{description: "ipv6", serviceCIDR: "fd00:10:96::/112", podCIDR: "fd00:10:244::/64"},
+		{description: "invalid service cidr", serviceCIDR: "10.96.0.0", shouldErr: true},
+		{description: "invalid pod cidr", serviceCIDR: DefaultServiceCIDR, podCIDR: "10.244.0.0/33", shouldErr: true},
+		{description: "too small for the dns ip", serviceCIDR: "10.96.0.0/29", shouldErr: true},
+		{description: "ipv6 too large", serviceCIDR: "fd00:10:96::/64", shouldErr: true},
+		{description: "mixed families", serviceCIDR: DefaultServiceCIDR, podCIDR: "fd00:10:244::/64", shouldErr: true},
+		{description: "overlapping", serviceCIDR: DefaultServiceCIDR, podCIDR: "10.100.0.0/16", shouldErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateNetworkCIDRs(test.serviceCIDR, test.podCIDR)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error for service CIDR %q and pod CIDR %q", test.serviceCIDR, test.podCIDR)
+			}
+		})
+	}
+}
+
+func TestValidateNodeIPFamily(t *testing.T) {
+	var tests = []struct {
+		description string
+		nodeIP      string
+		serviceCIDR string
+		podCIDR     string
+		shouldErr   bool
+	}{
+		{description: "ipv4", nodeIP: "192.168.99.100", serviceCIDR: DefaultServiceCIDR, podCIDR: "10.244.0.0/16"},
+		{description: "ipv6", nodeIP: "fd00::100", serviceCIDR: "fd00:10:96::/112", podCIDR: "fd00:10:244::/64"},
+		{description: "ipv6 service cidr", nodeIP: "192.168.99.100", serviceCIDR: "fd00:10:96::/112", shouldErr: true},
+		{description: "ipv6 pod cidr", nodeIP: "192.168.99.100", serviceCIDR: DefaultServiceCIDR, podCIDR: "fd00:10:244::/64", shouldErr: true},
+		{description: "ipv4 on ipv6 node", nodeIP: "fd00::100", serviceCIDR: DefaultServiceCIDR, shouldErr: true},
+		{description: "invalid node ip", nodeIP: "192.168.99", serviceCIDR: DefaultServiceCIDR, shouldErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateNodeIPFamily(test.nodeIP, test.serviceCIDR, test.podCIDR)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error for node IP %q, service CIDR %q and pod CIDR %q", test.nodeIP, test.serviceCIDR, test.podCIDR)
+			}
+		})
+	}
+}
@@ -61,6 +61,36 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 // If the certificate or key files already exist, they will be overwritten.
 // Any parent directories of the certPath or keyPath will be created as needed with file mode 0755.
 func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string) error {
+	template := x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   cn,
+			Organization: []string{"system:masters"},
+		},
+		NotAfter:    time.Now().Add(time.Hour * 24 * 365),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses: ips,
+		DNSNames:    alternateDNS,
+	}
+	return generateSignedCert(&template, certPath, keyPath, signerCertPath, signerKeyPath)
+}
+
+// GenerateClientCert generates a client certificate for the user cn in the given
+// organizations, which Kubernetes treats as the groups of the user.
+func GenerateClientCert(certPath, keyPath, cn string, organizations []string, validFor time.Duration, signerCertPath, signerKeyPath string) error {
+	template := x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   cn,
+			Organization: organizations,
+		},
+		NotAfter:    time.Now().Add(validFor),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	return generateSignedCert(&template, certPath, keyPath, signerCertPath, signerKeyPath)
+}
+
+// generateSignedCert signs template with the signer, after giving it a random
+// serial number
+func generateSignedCert(template *x509.Certificate, certPath, keyPath, signerCertPath, signerKeyPath string) error {
 	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
 	if err != nil {
 		return errors.Wrap(err, "Error reading file: signerCertPath")
@@ -86,29 +116,38 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 		return errors.Wrap(err, "Error parsing prive key: decodedSignerKey.Bytes")
 	}
 
-	template := x509.Certificate{
-		SerialNumber: big.NewInt(2),
-		Subject: pkix.Name{
-			CommonName:   cn,
-			Organization: []string{"system:masters"},
-		},
-		NotBefore: time.Now(),
-		NotAfter:  time.Now().Add(time.Hour * 24 * 365),
-
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
-		BasicConstraintsValid: true,
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return errors.Wrap(err, "Error generating serial number")
 	}
-
-	template.IPAddresses = append(template.IPAddresses, ips...)
-	template.DNSNames = append(template.DNSNames, alternateDNS...)
+	template.SerialNumber = serial
+	template.NotBefore = time.Now()
+	template.KeyUsage = x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
+	template.BasicConstraintsValid = true
 
 	priv, err := loadOrGeneratePrivateKey(keyPath)
 	if err != nil {
 		return errors.Wrap(err, "Error loading or generating private key: keyPath")
 	}
 
-	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
+	return writeCertsAndKeys(template, certPath, priv, keyPath, signerCert, signerKey)
+}
+
+// ReadCert reads a PEM encoded certificate
+func ReadCert(certPath string) (*x509.Certificate, error) {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading certificate %s", certPath)
+	}
+	decodedCert, _ := pem.Decode(certBytes)
+	if decodedCert == nil {
+		return nil, errors.Errorf("Unable to decode certificate %s", certPath)
+	}
+	cert, err := x509.ParseCertificate(decodedCert.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing certificate %s", certPath)
+	}
+	return cert, nil
 }
 
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
@@ -23,7 +23,10 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -148,3 +151,56 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateClientCert(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	signerCertPath := filepath.Join(tmpDir, "ca.crt")
+	signerKeyPath := filepath.Join(tmpDir, "ca.key")
+	if err := GenerateCACert(signerCertPath, signerKeyPath, constants.APIServerName); err != nil {
+		t.Fatalf("Error generating signer cert: %v", err)
+	}
+
+	certPath := filepath.Join(tmpDir, "jane.crt")
+	keyPath := filepath.Join(tmpDir, "jane.key")
+	groups := []string{"developers", "testers"}
+	if err := GenerateClientCert(certPath, keyPath, "jane", groups, time.Hour, signerCertPath, signerKeyPath); err != nil {
+		t.Fatalf("GenerateClientCert() error = %v", err)
+	}
+
+	c, err := ReadCert(certPath)
+	if err != nil {
+		t.Fatalf("Error reading cert: %v", err)
+	}
+	if c.Subject.CommonName != "jane" {
+		t.Errorf("Expected CN jane, got %s", c.Subject.CommonName)
+	}
+	// The organizations are a set, encoding doesn't keep their order
+	orgs := append([]string{}, c.Subject.Organization...)
+	sort.Strings(orgs)
+	if !reflect.DeepEqual(orgs, groups) {
+		t.Errorf("Expected organizations %v, got %v", groups, c.Subject.Organization)
+	}
+	if c.NotAfter.After(time.Now().Add(time.Hour)) {
+		t.Errorf("Expected cert to expire within an hour, got %s", c.NotAfter)
+	}
+	if !reflect.DeepEqual(c.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}) {
+		t.Errorf("Expected a client auth only cert, got %v", c.ExtKeyUsage)
+	}
+
+	// Certs of the same signer need different serial numbers
+	if err := GenerateClientCert(certPath, keyPath, "jane", groups, time.Hour, signerCertPath, signerKeyPath); err != nil {
+		t.Fatalf("GenerateClientCert() error = %v", err)
+	}
+	renewed, err := ReadCert(certPath)
+	if err != nil {
+		t.Fatalf("Error reading cert: %v", err)
+	}
+	if renewed.SerialNumber.Cmp(c.SerialNumber) == 0 {
+		t.Errorf("Expected a new serial number, got %s twice", c.SerialNumber)
+	}
+}
@@ -73,9 +73,13 @@ func (f DefaultDownloader) CacheMinikubeISOFromURL(isoURL string) error {
 	}
 
 	fmt.Println("Downloading Minikube ISO")
-	if err := download.ToFile(isoURL, f.GetISOCacheFilepath(isoURL), options); err != nil {
+	isoPath := f.GetISOCacheFilepath(isoURL)
+	if err := download.ToFile(isoURL, isoPath, options); err != nil {
 		return errors.Wrap(err, "Error downloading Minikube ISO")
 	}
+	if err := WriteChecksumFile(isoPath); err != nil {
+		return errors.Wrap(err, "Error writing Minikube ISO checksum")
+	}
 
 	return nil
 }
@@ -101,7 +105,16 @@ func (f DefaultDownloader) GetISOCacheFilepath(isoURL string) string {
 }
 
 func (f DefaultDownloader) IsMinikubeISOCached(isoURL string) bool {
-	if _, err := os.Stat(f.GetISOCacheFilepath(isoURL)); os.IsNotExist(err) {
+	isoPath := f.GetISOCacheFilepath(isoURL)
+	if _, err := os.Stat(isoPath); os.IsNotExist(err) {
+		return false
+	}
+	checksumURL := ""
+	if isoURL == constants.DefaultIsoUrl {
+		checksumURL = constants.DefaultIsoShaUrl
+	}
+	if err := VerifyCachedFile(isoPath, checksumURL); err != nil {
+		glog.Infof("Cached ISO failed verification, it will be downloaded again: %s", err)
 		return false
 	}
 	return true
@@ -73,6 +73,9 @@ func TestCacheMinikubeISOFromURL(t *testing.T) {
 		t.Fatalf("Expected transfers to contain: %s. It was: %s", contents, transferred)
 	}
 
+	if err := VerifyChecksumFile(isoPath); err != nil {
+		t.Fatalf("Expected a valid checksum to be recorded for the ISO: %s", err)
+	}
 }
 
 func TestShouldCacheMinikubeISO(t *testing.T) {
@@ -103,11 +106,23 @@ func TestIsMinikubeISOCached(t *testing.T) {
 		t.Fatalf("Expected IsMinikubeISOCached with input %s to return %t but instead got: %t", testFileURI, expected, out)
 	}
 
-	ioutil.WriteFile(filepath.Join(constants.GetMinipath(), "cache", "iso", "minikube-test.iso"), []byte(testISOString), os.FileMode(int(0644)))
+	isoPath := filepath.Join(constants.GetMinipath(), "cache", "iso", "minikube-test.iso")
+	ioutil.WriteFile(isoPath, []byte(testISOString), os.FileMode(int(0644)))
 
+	// An ISO cached before checksums were recorded gets a checksum.
 	expected = true
 	if out := dler.IsMinikubeISOCached(testFileURI); out != expected {
 		t.Fatalf("Expected IsMinikubeISOCached with input %s to return %t but instead got: %t", testFileURI, expected, out)
 	}
+	if err := VerifyChecksumFile(isoPath); err != nil {
+		t.Fatalf("Expected a checksum to be recorded for a cached ISO: %s", err)
+	}
+
+	// Simulate a truncated download.
+	ioutil.WriteFile(isoPath, []byte(testISOString[:2]), os.FileMode(int(0644)))
+	expected = false
+	if out := dler.IsMinikubeISOCached(testFileURI); out != expected {
+		t.Fatalf("Expected IsMinikubeISOCached with input %s to return %t but instead got: %t", testFileURI, expected, out)
+	}
 
 }
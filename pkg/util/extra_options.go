@@ -25,9 +25,14 @@ type ExtraOption struct {
 	Component string
 	Key       string
 	Value     string
+	// Remove drops a flag minikube sets by default, e.g. kubelet.cadvisor-port-
+	Remove bool `json:",omitempty"`
 }
 
 func (e *ExtraOption) String() string {
+	if e.Remove {
+		return fmt.Sprintf("%s.%s-", e.Component, e.Key)
+	}
 	return fmt.Sprintf("%s.%s=%s", e.Component, e.Key, e.Value)
 }
 
@@ -42,15 +47,24 @@ func (es *ExtraOptionSlice) Set(value string) error {
 
 	remainder := strings.Join(componentSplit[1:], "")
 
+	var e ExtraOption
 	keySplit := strings.SplitN(remainder, "=", 2)
-	if len(keySplit) != 2 {
-		return fmt.Errorf("Invalid value for ExtraOption flag. Value must contain one equal sign: %s", value)
-	}
-
-	e := ExtraOption{
-		Component: componentSplit[0],
-		Key:       keySplit[0],
-		Value:     keySplit[1],
+	switch {
+	case len(keySplit) == 2:
+		e = ExtraOption{
+			Component: componentSplit[0],
+			Key:       keySplit[0],
+			Value:     keySplit[1],
+		}
+	// A trailing dash instead of a value removes the flag
+	case len(remainder) > 1 && strings.HasSuffix(remainder, "-"):
+		e = ExtraOption{
+			Component: componentSplit[0],
+			Key:       strings.TrimSuffix(remainder, "-"),
+			Remove:    true,
+		}
+	default:
+		return fmt.Errorf("Invalid value for ExtraOption flag. Value must contain one equal sign, or end with a dash to remove the flag: %s", value)
 	}
 	*es = append(*es, e)
 	return nil
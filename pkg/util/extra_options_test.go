@@ -34,6 +34,8 @@ func TestInvalidFlags(t *testing.T) {
 		{"-e", "foo", "-e", "foo", "-e", "foo"},
 		{"-e", "foo", "-e", "foo.bar=baz"},
 		{"-e", "foo", "-e", "foo.bar=baz"},
+		// Removals without a key
+		{"-e", "foo.-"},
 	} {
 		var flags flag.FlagSet
 		flags.Init("test", flag.ContinueOnError)
@@ -63,6 +65,14 @@ func TestValidFlags(t *testing.T) {
 			[]string{"-e", "foo.bar=baz", "-e", "foo.bar.baz=bat"},
 			ExtraOptionSlice{ExtraOption{Component: "foo", Key: "bar", Value: "baz"}, ExtraOption{Component: "foo", Key: "bar.baz", Value: "bat"}},
 		},
+		{
+			[]string{"-e", "kubelet.cadvisor-port-"},
+			ExtraOptionSlice{ExtraOption{Component: "kubelet", Key: "cadvisor-port", Remove: true}},
+		},
+		{
+			[]string{"-e", "foo.bar=baz-"},
+			ExtraOptionSlice{ExtraOption{Component: "foo", Key: "bar", Value: "baz-"}},
+		},
 	} {
 		var flags flag.FlagSet
 		flags.Init("test", flag.ContinueOnError)
@@ -70,11 +80,11 @@ func TestValidFlags(t *testing.T) {
 		var e ExtraOptionSlice
 		flags.Var(&e, "e", "usage")
 		if err := flags.Parse(tc.args); err != nil {
-			t.Errorf("Unexpected error: %s for %s.", err, tc)
+			t.Errorf("Unexpected error: %s for %v.", err, tc)
 		}
 
 		if !reflect.DeepEqual(e, tc.values) {
-			t.Errorf("Wrong parsed value. Expected %s, got %s", tc.values, e)
+			t.Errorf("Wrong parsed value. Expected %v, got %v", tc.values, e)
 		}
 	}
 }
@@ -0,0 +1,277 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+)
+
+// FeatureGateComponents are the components that accept --feature-gates
+var FeatureGateComponents = []string{"apiserver", "controller-manager", "scheduler", "kubelet", "proxy"}
+
+// FeatureGate describes a feature gate and the versions it exists in.
+type FeatureGate struct {
+	Name string
+	// Components are the components that know the gate. Nil means all of FeatureGateComponents.
+	Components []string
+	// Added is the first version with the gate. The zero value means all versions.
+	Added semver.Version
+	// Removed is the first version without the gate.
+	Removed semver.Version
+}
+
+// The gates of k8s.io/kubernetes/pkg/features and k8s.io/apiserver/pkg/features
+// are registered by every component, those of the apiextensions apiserver only
+// by the apiserver.
+var featureGates = []FeatureGate{
+	{Name: "Accelerators", Removed: mustVersion("1.11.0-alpha.0")},
+	{Name: "AdvancedAuditing", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "AllAlpha"},
+	{Name: "APIListChunking", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "APIResponseCompression", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "AppArmor"},
+	{Name: "BlockVolume", Added: mustVersion("1.9.0-alpha.0")},
+	{Name: "CPUManager", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "CSIPersistentVolume", Added: mustVersion("1.9.0-alpha.0")},
+	{Name: "CustomPodDNS", Added: mustVersion("1.9.0-alpha.0")},
+	{Name: "CustomResourceSubresources", Components: []string{"apiserver"}, Added: mustVersion("1.10.0-alpha.0")},
+	{Name: "CustomResourceValidation", Components: []string{"apiserver"}, Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "DebugContainers", Added: mustVersion("1.10.0-alpha.0")},
+	{Name: "DevicePlugins", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "DynamicKubeletConfig"},
+	{Name: "EnableEquivalenceClassCache", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "ExpandPersistentVolumes", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "ExperimentalCriticalPodAnnotation"},
+	{Name: "ExperimentalHostUserNamespaceDefaulting"},
+	{Name: "HugePages", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "Initializers", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "LocalStorageCapacityIsolation", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "MountContainers", Added: mustVersion("1.9.0-alpha.0")},
+	{Name: "MountPropagation", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "PersistentLocalVolumes", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "PodPriority", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "PodShareProcessNamespace", Added: mustVersion("1.10.0-alpha.0")},
+	{Name: "PVCProtection", Added: mustVersion("1.9.0-alpha.0"), Removed: mustVersion("1.11.0-alpha.0")},
+	{Name: "ResourceLimitsPriorityFunction", Added: mustVersion("1.9.0-alpha.0")},
+	{Name: "RotateKubeletClientCertificate", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "RotateKubeletServerCertificate", Added: mustVersion("1.7.0-alpha.0")},
+	{Name: "RunAsGroup", Added: mustVersion("1.10.0-alpha.0")},
+	{Name: "ServiceNodeExclusion", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "StorageObjectInUseProtection", Added: mustVersion("1.10.0-alpha.0")},
+	{Name: "StreamingProxyRedirects"},
+	{Name: "SupportIPVSProxyMode", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "TaintBasedEvictions"},
+	{Name: "TaintNodesByCondition", Added: mustVersion("1.8.0-alpha.0")},
+	{Name: "TokenRequest", Added: mustVersion("1.10.0-alpha.0")},
+	{Name: "VolumeScheduling", Added: mustVersion("1.9.0-alpha.0")},
+	{Name: "VolumeSubpath", Added: mustVersion("1.10.0-alpha.0")},
+}
+
+// FeatureGates are the parsed value of --feature-gates. Gates without a
+// component apply to every component that knows them, gates written as
+// component:Name=bool apply to that component only and take precedence.
+type FeatureGates struct {
+	Global     map[string]bool
+	Components map[string]map[string]bool
+}
+
+// ParseFeatureGates parses a comma separated list of Name=bool and component:Name=bool pairs
+func ParseFeatureGates(s string) (FeatureGates, error) {
+	gates := FeatureGates{Global: map[string]bool{}, Components: map[string]map[string]bool{}}
+	if s == "" {
+		return gates, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return gates, fmt.Errorf("invalid feature gate %q, expected Name=bool or component:Name=bool", pair)
+		}
+		value, err := strconv.ParseBool(kv[1])
+		if err != nil {
+			return gates, errors.Wrapf(err, "invalid value of feature gate %s", kv[0])
+		}
+
+		name := kv[0]
+		if i := strings.Index(name, ":"); i >= 0 {
+			component := name[:i]
+			name = name[i+1:]
+			if !isFeatureGateComponent(component) {
+				return gates, fmt.Errorf("invalid component %q in feature gate %q. Valid components are: %v", component, pair, FeatureGateComponents)
+			}
+			if gates.Components[component] == nil {
+				gates.Components[component] = map[string]bool{}
+			}
+			gates.Components[component][name] = value
+			continue
+		}
+		gates.Global[name] = value
+	}
+	return gates, nil
+}
+
+func isFeatureGateComponent(component string) bool {
+	for _, c := range FeatureGateComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+func findFeatureGate(name string) (FeatureGate, bool) {
+	for _, g := range featureGates {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return FeatureGate{}, false
+}
+
+// hasFeatureGate returns whether a component of this version knows the gate.
+// A nil version only checks the component.
+func (g FeatureGate) hasFeatureGate(component string, version *semver.Version) bool {
+	if g.Components != nil {
+		found := false
+		for _, c := range g.Components {
+			if c == component {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if version == nil {
+		return true
+	}
+	if !isZeroVersion(g.Added) && version.LT(g.Added) {
+		return false
+	}
+	if !isZeroVersion(g.Removed) && version.GTE(g.Removed) {
+		return false
+	}
+	return true
+}
+
+// ForComponent returns the gates a component of this version is started with.
+// Gates minikube doesn't know are passed to every component that accepts
+// --feature-gates.
+func (f FeatureGates) ForComponent(component string, version *semver.Version) map[string]bool {
+	gates := map[string]bool{}
+	if !isFeatureGateComponent(component) {
+		return gates
+	}
+	for name, value := range f.Global {
+		if g, ok := findFeatureGate(name); ok && !g.hasFeatureGate(component, version) {
+			continue
+		}
+		gates[name] = value
+	}
+	for name, value := range f.Components[component] {
+		gates[name] = value
+	}
+	return gates
+}
+
+// Merged returns the gates of all the components together, for localkube which
+// runs them in a single process. It fails if two components, or a component and
+// the global gates, set a gate differently.
+func (f FeatureGates) Merged() (map[string]bool, error) {
+	gates := map[string]bool{}
+	for name, value := range f.Global {
+		gates[name] = value
+	}
+	set := map[string]string{}
+	for _, component := range FeatureGateComponents {
+		for _, name := range sortedKeys(f.Components[component]) {
+			value := f.Components[component][name]
+			if other, ok := set[name]; ok && gates[name] != value {
+				return nil, fmt.Errorf("feature gate %s is set differently for %s and %s, which run in the same process", name, other, component)
+			}
+			if global, ok := f.Global[name]; ok && global != value {
+				return nil, fmt.Errorf("feature gate %s is set to %t for all components and to %t for %s, which run in the same process", name, global, value, component)
+			}
+			set[name] = component
+			gates[name] = value
+		}
+	}
+	return gates, nil
+}
+
+// FeatureGatesFlag returns the value of --feature-gates for a set of gates
+func FeatureGatesFlag(gates map[string]bool) string {
+	var pairs []string
+	for name, value := range gates {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ValidateFeatureGates checks the names of the gates against the gates of this
+// version, or of any version if it is nil. Gates set for one component must be
+// known by that component. Gates minikube doesn't know, such as those of newer
+// versions, only return a warning.
+func ValidateFeatureGates(f FeatureGates, version *semver.Version) ([]string, error) {
+	var warnings []string
+	check := func(name, component string) error {
+		g, ok := findFeatureGate(name)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s is not a feature gate minikube knows, it is passed to the components as is", name))
+			return nil
+		}
+		if version != nil && !isZeroVersion(g.Added) && version.LT(g.Added) {
+			return fmt.Errorf("feature gate %s was added in Kubernetes v%s and can't be used with v%s. Use --force to pass it anyway.", name, g.Added, version)
+		}
+		if version != nil && !isZeroVersion(g.Removed) && version.GTE(g.Removed) {
+			return fmt.Errorf("feature gate %s was removed in Kubernetes v%s. Use --force to pass it anyway.", name, g.Removed)
+		}
+		if component != "" && !g.hasFeatureGate(component, nil) {
+			return fmt.Errorf("feature gate %s is not a gate of %s, only of %v. Use --force to pass it anyway.", name, component, g.Components)
+		}
+		return nil
+	}
+
+	for _, name := range sortedKeys(f.Global) {
+		if err := check(name, ""); err != nil {
+			return nil, err
+		}
+	}
+	for _, component := range FeatureGateComponents {
+		for _, name := range sortedKeys(f.Components[component]) {
+			if err := check(name, component); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver"
+)
+
+func TestParseFeatureGates(t *testing.T) {
+	var tests = []struct {
+		description string
+		gates       string
+		expected    FeatureGates
+		shouldErr   bool
+	}{
+		{
+			description: "empty",
+			expected:    FeatureGates{Global: map[string]bool{}, Components: map[string]map[string]bool{}},
+		},
+		{
+			description: "global and component gates",
+			gates:       "HugePages=true,kubelet:DevicePlugins=true,apiserver:HugePages=false",
+			expected: FeatureGates{
+				Global: map[string]bool{"HugePages": true},
+				Components: map[string]map[string]bool{
+					"kubelet":   {"DevicePlugins": true},
+					"apiserver": {"HugePages": false},
+				},
+			},
+		},
+		{
+			description: "missing value",
+			gates:       "HugePages",
+			shouldErr:   true,
+		},
+		{
+			description: "invalid value",
+			gates:       "HugePages=yes please",
+			shouldErr:   true,
+		},
+		{
+			description: "unknown component",
+			gates:       "etcd:HugePages=true",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			gates, err := ParseFeatureGates(test.gates)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("Expected an error, got %v", gates)
+			}
+			if !test.shouldErr && !reflect.DeepEqual(gates, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, gates)
+			}
+		})
+	}
+}
+
+func TestFeatureGatesForComponent(t *testing.T) {
+	gates, err := ParseFeatureGates("CustomResourceValidation=true,HugePages=true,Unknown=false,kubelet:HugePages=false")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	v := semver.MustParse("1.9.0")
+	var tests = []struct {
+		component string
+		expected  map[string]bool
+	}{
+		{component: "apiserver", expected: map[string]bool{"CustomResourceValidation": true, "HugePages": true, "Unknown": false}},
+		{component: "kubelet", expected: map[string]bool{"HugePages": false, "Unknown": false}},
+		{component: "scheduler", expected: map[string]bool{"HugePages": true, "Unknown": false}},
+		{component: "etcd", expected: map[string]bool{}},
+	}
+	for _, test := range tests {
+		if actual := gates.ForComponent(test.component, &v); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Gates of %s: expected %v, got %v", test.component, test.expected, actual)
+		}
+	}
+
+	old := semver.MustParse("1.7.0")
+	if actual := gates.ForComponent("scheduler", &old); actual["HugePages"] {
+		t.Errorf("Expected HugePages to be left out before it was added, got %v", actual)
+	}
+	if flag := FeatureGatesFlag(gates.ForComponent("kubelet", &v)); flag != "HugePages=false,Unknown=false" {
+		t.Errorf("Unexpected flag value %s", flag)
+	}
+}
+
+func TestFeatureGatesMerged(t *testing.T) {
+	gates, err := ParseFeatureGates("HugePages=true,kubelet:DevicePlugins=true,proxy:SupportIPVSProxyMode=true")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	merged, err := gates.Merged()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := map[string]bool{"HugePages": true, "DevicePlugins": true, "SupportIPVSProxyMode": true}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+
+	gates, err = ParseFeatureGates("kubelet:HugePages=true,apiserver:HugePages=false")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := gates.Merged(); err == nil {
+		t.Errorf("Expected an error for a gate set differently for two components")
+	}
+
+	gates, err = ParseFeatureGates("HugePages=true,kubelet:HugePages=false")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := gates.Merged(); err == nil {
+		t.Errorf("Expected an error for a gate set differently for all components and one component")
+	}
+}
+
+func TestValidateFeatureGates(t *testing.T) {
+	v := semver.MustParse("1.9.0")
+	var tests = []struct {
+		description string
+		gates       string
+		version     *semver.Version
+		warnings    int
+		shouldErr   bool
+	}{
+		{description: "known gates", gates: "HugePages=true,kubelet:DevicePlugins=true", version: &v},
+		{description: "unknown gate", gates: "HugePage=true,kubelet:NewGate=true", version: &v, warnings: 2},
+		{description: "added later", gates: "TokenRequest=true", version: &v, shouldErr: true},
+		{description: "no version", gates: "TokenRequest=true"},
+		{description: "gate of another component", gates: "kubelet:CustomResourceValidation=true", version: &v, shouldErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			gates, err := ParseFeatureGates(test.gates)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			warnings, err := ValidateFeatureGates(gates, test.version)
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error for %s", test.gates)
+			}
+			if len(warnings) != test.warnings {
+				t.Errorf("Expected %d warnings for %s, got %v", test.warnings, test.gates, warnings)
+			}
+		})
+	}
+}
@@ -50,6 +50,10 @@ type KubeConfigSetup struct {
 	// ClientKey is the path to a client key file for TLS.
 	ClientKey string
 
+	// UserName and ContextName default to the name of the cluster
+	UserName    string
+	ContextName string
+
 	// Should the current context be kept when setting up this one
 	KeepContext bool
 
@@ -76,6 +80,9 @@ func PopulateKubeConfig(cfg *KubeConfigSetup, kubecfg *api.Config) {
 
 	// user
 	userName := cfg.ClusterName
+	if cfg.UserName != "" {
+		userName = cfg.UserName
+	}
 	user := api.NewAuthInfo()
 	user.ClientCertificate = cfg.ClientCertificate
 	user.ClientKey = cfg.ClientKey
@@ -83,6 +90,9 @@ func PopulateKubeConfig(cfg *KubeConfigSetup, kubecfg *api.Config) {
 
 	// context
 	contextName := cfg.ClusterName
+	if cfg.ContextName != "" {
+		contextName = cfg.ContextName
+	}
 	context := api.NewContext()
 	context.Cluster = cfg.ClusterName
 	context.AuthInfo = userName
@@ -90,7 +100,7 @@ func PopulateKubeConfig(cfg *KubeConfigSetup, kubecfg *api.Config) {
 
 	// Only set current context to minikube if the user has not used the keepContext flag
 	if !cfg.KeepContext {
-		kubecfg.CurrentContext = cfg.ClusterName
+		kubecfg.CurrentContext = contextName
 	}
 }
 
@@ -193,41 +203,49 @@ func decode(data []byte) (*api.Config, error) {
 	return config.(*api.Config), nil
 }
 
-// GetKubeConfigStatus verifys the ip stored in kubeconfig.
-func GetKubeConfigStatus(ip net.IP, filename string, machineName string) (bool, error) {
+// GetKubeConfigStatus verifys the ip and port stored in kubeconfig.
+func GetKubeConfigStatus(ip net.IP, port int, filename string, machineName string) (bool, error) {
 	if ip == nil {
 		return false, fmt.Errorf("Error, empty ip passed")
 	}
-	kip, err := getIPFromKubeConfig(filename, machineName)
+	kip, kport, err := getEndpointFromKubeConfig(filename, machineName)
 	if err != nil {
 		return false, err
 	}
-	if kip.Equal(ip) {
+	if kip.Equal(ip) && kport == port {
 		return true, nil
 	}
-	// Kubeconfig IP misconfigured
+	// Kubeconfig IP or port misconfigured
 	return false, nil
 
 }
 
-// UpdateKubeconfigIP overwrites the IP stored in kubeconfig with the provided IP.
-func UpdateKubeconfigIP(ip net.IP, filename string, machineName string) (bool, error) {
+// UpdateKubeconfigIP overwrites the IP and port stored in kubeconfig with the provided ones.
+// Every cluster that pointed at the old endpoint of the machine is updated.
+func UpdateKubeconfigIP(ip net.IP, port int, filename string, machineName string) (bool, error) {
 	if ip == nil {
 		return false, fmt.Errorf("Error, empty ip passed")
 	}
-	kip, err := getIPFromKubeConfig(filename, machineName)
+	kip, kport, err := getEndpointFromKubeConfig(filename, machineName)
 	if err != nil {
 		return false, err
 	}
-	if kip.Equal(ip) {
+	if kip.Equal(ip) && kport == port {
 		return false, nil
 	}
 	con, err := ReadConfigOrNew(filename)
 	if err != nil {
 		return false, errors.Wrap(err, "Error getting kubeconfig status")
 	}
-	// Safe to lookup server because if field non-existent getIPFromKubeconfig would have given an error
-	con.Clusters[machineName].Server = "https://" + ip.String() + ":" + strconv.Itoa(util.APIServerPort)
+	// Safe to lookup server because if field non-existent getEndpointFromKubeConfig would have given an error
+	oldServer := con.Clusters[machineName].Server
+	server := "https://" + net.JoinHostPort(ip.String(), strconv.Itoa(port))
+	// Other entries for the same cluster, such as copies made for other users, are updated too
+	for _, cluster := range con.Clusters {
+		if cluster.Server == oldServer {
+			cluster.Server = server
+		}
+	}
 	err = WriteConfig(con, filename)
 	if err != nil {
 		return false, err
@@ -236,24 +254,28 @@ func UpdateKubeconfigIP(ip net.IP, filename string, machineName string) (bool, e
 	return true, nil
 }
 
-// getIPFromKubeConfig returns the IP address stored for minikube in the kubeconfig specified
-func getIPFromKubeConfig(filename, machineName string) (net.IP, error) {
+// getEndpointFromKubeConfig returns the IP address and port stored for minikube in the kubeconfig specified.
+// The port is 0 if the server has none.
+func getEndpointFromKubeConfig(filename, machineName string) (net.IP, int, error) {
 	con, err := ReadConfigOrNew(filename)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error getting kubeconfig status")
+		return nil, 0, errors.Wrap(err, "Error getting kubeconfig status")
 	}
 	cluster, ok := con.Clusters[machineName]
 	if !ok {
-		return nil, errors.Errorf("Kubeconfig does not have a record of the machine cluster")
+		return nil, 0, errors.Errorf("Kubeconfig does not have a record of the machine cluster")
 	}
 	kurl, err := url.Parse(cluster.Server)
 	if err != nil {
-		return net.ParseIP(cluster.Server), nil
+		return net.ParseIP(cluster.Server), 0, nil
+	}
+	kip, kport, err := net.SplitHostPort(kurl.Host)
+	if err != nil {
+		return net.ParseIP(kurl.Host), 0, nil
 	}
-	kip, _, err := net.SplitHostPort(kurl.Host)
+	port, err := strconv.Atoi(kport)
 	if err != nil {
-		return net.ParseIP(kurl.Host), nil
+		return nil, 0, errors.Wrapf(err, "parsing port of %s", cluster.Server)
 	}
-	ip := net.ParseIP(kip)
-	return ip, nil
+	return net.ParseIP(kip), port, nil
 }
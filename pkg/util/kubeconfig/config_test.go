@@ -173,11 +173,39 @@ func TestSetupKubeConfig(t *testing.T) {
 	}
 }
 
+func TestPopulateKubeConfigUser(t *testing.T) {
+	cfg := api.NewConfig()
+	PopulateKubeConfig(&KubeConfigSetup{
+		ClusterName:          "minikube",
+		ClusterServerAddress: "https://192.168.99.100:8443",
+		ClientCertificate:    "/home/jane.crt",
+		ClientKey:            "/home/jane.key",
+		CertificateAuthority: "/home/ca.crt",
+		UserName:             "jane",
+		ContextName:          "jane@minikube",
+	}, cfg)
+
+	if cfg.CurrentContext != "jane@minikube" {
+		t.Errorf("Expected current context jane@minikube, got %s", cfg.CurrentContext)
+	}
+	context, ok := cfg.Contexts["jane@minikube"]
+	if !ok || context.Cluster != "minikube" || context.AuthInfo != "jane" {
+		t.Errorf("Unexpected context: %+v", context)
+	}
+	if user, ok := cfg.AuthInfos["jane"]; !ok || user.ClientCertificate != "/home/jane.crt" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	if _, ok := cfg.AuthInfos["minikube"]; ok {
+		t.Errorf("Expected no minikube user")
+	}
+}
+
 func TestGetKubeConfigStatus(t *testing.T) {
 
 	var tests = []struct {
 		description string
 		ip          net.IP
+		port        int
 		existing    []byte
 		err         bool
 		status      bool
@@ -197,21 +225,29 @@ func TestGetKubeConfigStatus(t *testing.T) {
 		{
 			description: "exactly matching ip",
 			ip:          net.ParseIP("192.168.10.100"),
+			port:        8443,
 			existing:    fakeKubeCfg2,
 			status:      true,
 		},
 		{
 			description: "different ips",
 			ip:          net.ParseIP("192.168.10.100"),
+			port:        8443,
 			existing:    fakeKubeCfg3,
 		},
+		{
+			description: "different ports",
+			ip:          net.ParseIP("192.168.10.100"),
+			port:        6443,
+			existing:    fakeKubeCfg2,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
 			t.Parallel()
 			configFilename := tempFile(t, test.existing)
-			statusActual, err := GetKubeConfigStatus(test.ip, configFilename, "minikube")
+			statusActual, err := GetKubeConfigStatus(test.ip, test.port, configFilename, "minikube")
 			if err != nil && !test.err {
 				t.Errorf("Got unexpected error: %s", err)
 			}
@@ -231,6 +267,7 @@ func TestUpdateKubeconfigIP(t *testing.T) {
 	var tests = []struct {
 		description string
 		ip          net.IP
+		port        int
 		existing    []byte
 		err         bool
 		status      bool
@@ -253,22 +290,31 @@ func TestUpdateKubeconfigIP(t *testing.T) {
 		{
 			description: "same IP",
 			ip:          net.ParseIP("192.168.10.100"),
+			port:        8443,
 			existing:    fakeKubeCfg2,
 			expCfg:      fakeKubeCfg2,
 		},
 		{
 			description: "different IP",
 			ip:          net.ParseIP("192.168.10.100"),
+			port:        8443,
 			existing:    fakeKubeCfg3,
 			status:      true,
 			expCfg:      fakeKubeCfg2,
 		},
+		{
+			description: "different port",
+			ip:          net.ParseIP("192.168.10.100"),
+			port:        6443,
+			existing:    fakeKubeCfg2,
+			status:      true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
 			t.Parallel()
 			configFilename := tempFile(t, test.existing)
-			statusActual, err := UpdateKubeconfigIP(test.ip, configFilename, "minikube")
+			statusActual, err := UpdateKubeconfigIP(test.ip, test.port, configFilename, "minikube")
 			if err != nil && !test.err {
 				t.Errorf("Got unexpected error: %s", err)
 			}
@@ -283,6 +329,42 @@ func TestUpdateKubeconfigIP(t *testing.T) {
 	}
 }
 
+func TestUpdateKubeconfigIPAllClusters(t *testing.T) {
+	existing := []byte(`
+apiVersion: v1
+clusters:
+- cluster:
+    server: https://192.168.1.1:8443
+  name: minikube
+- cluster:
+    server: https://192.168.1.1:8443
+  name: minikube-copy
+- cluster:
+    server: https://10.0.0.5:6443
+  name: other
+kind: Config
+`)
+	configFilename := tempFile(t, existing)
+	defer os.Remove(configFilename)
+	if _, err := UpdateKubeconfigIP(net.ParseIP("192.168.10.100"), 8443, configFilename, "minikube"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	con, err := ReadConfigOrNew(configFilename)
+	if err != nil {
+		t.Fatalf("Error reading kubeconfig: %s", err)
+	}
+	expected := map[string]string{
+		"minikube":      "https://192.168.10.100:8443",
+		"minikube-copy": "https://192.168.10.100:8443",
+		"other":         "https://10.0.0.5:6443",
+	}
+	for name, server := range expected {
+		if con.Clusters[name].Server != server {
+			t.Errorf("Expected cluster %s to point at %s, got %s", name, server, con.Clusters[name].Server)
+		}
+	}
+}
+
 func TestEmptyConfig(t *testing.T) {
 	tmp := tempFile(t, []byte{})
 	defer os.Remove(tmp)
@@ -333,18 +415,20 @@ func TestNewConfig(t *testing.T) {
 	}
 }
 
-func TestGetIPFromKubeConfig(t *testing.T) {
+func TestGetEndpointFromKubeConfig(t *testing.T) {
 
 	var tests = []struct {
 		description string
 		cfg         []byte
 		ip          net.IP
+		port        int
 		err         bool
 	}{
 		{
 			description: "normal IP",
 			cfg:         fakeKubeCfg2,
 			ip:          net.ParseIP("192.168.10.100"),
+			port:        8443,
 		},
 		{
 			description: "no minikube cluster",
@@ -355,7 +439,7 @@ func TestGetIPFromKubeConfig(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
 			configFilename := tempFile(t, test.cfg)
-			ip, err := getIPFromKubeConfig(configFilename, "minikube")
+			ip, port, err := getEndpointFromKubeConfig(configFilename, "minikube")
 			if err != nil && !test.err {
 				t.Errorf("Got unexpected error: %s", err)
 			}
@@ -365,6 +449,9 @@ func TestGetIPFromKubeConfig(t *testing.T) {
 			if !ip.Equal(test.ip) {
 				t.Errorf("IP returned: %s does not match ip given: %s", ip, test.ip)
 			}
+			if port != test.port {
+				t.Errorf("Port returned: %d does not match port given: %d", port, test.port)
+			}
 		})
 	}
 }